@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/MoonMoon1919/gignore"
+)
+
+var (
+	noPathsError         = errors.New("no path specified")
+	stdinWithPathsError  = errors.New("cannot specify pathnames with -stdin")
+	nonMatchingError     = errors.New("-non-matching is only valid with -v")
+	pathOutsideFileError = errors.New("path is outside of the directory the ignore files are loaded from")
+	stdinReadError       = errors.New("failed to read paths from stdin")
+)
+
+type checkIgnoreOptions struct {
+	verbose     bool
+	nonMatching bool
+	nul         bool
+}
+
+// runCheckIgnore mirrors `git check-ignore`. Like git, every ignore file of the repository applies:
+// the ignore files named like -file are loaded from the root of the git repository containing it,
+// or from its directory outside of a repository. It exits with 0 if at least one path matched,
+// and with 1 if none did.
+func runCheckIgnore(args []string, env environment) int {
+	flags, file := newFlagSet("check-ignore", env)
+	flags.Usage = func() {
+		fmt.Fprintln(env.stderr, "Usage: gignore check-ignore [flags] <path>...")
+		fmt.Fprintln(env.stderr, "       gignore check-ignore [flags] -stdin")
+		flags.PrintDefaults()
+	}
+
+	var opts checkIgnoreOptions
+	flags.BoolVar(&opts.verbose, "v", false, "print the matching rule for each path")
+	flags.BoolVar(&opts.nonMatching, "n", false, "also print paths that match no rule (requires -v)")
+	flags.BoolVar(&opts.nonMatching, "non-matching", false, "same as -n")
+	flags.BoolVar(&opts.nul, "z", false, "separate input paths and output records with NUL")
+	stdin := flags.Bool("stdin", false, "read paths from stdin, one per line")
+	noIndex := flags.Bool("no-index", false, "don't read the git index, so tracked paths are checked too")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if opts.nonMatching && !opts.verbose {
+		return fail(env, "check-ignore", nonMatchingError, exitError)
+	}
+
+	targets := flags.Args()
+	if *stdin {
+		if len(targets) > 0 {
+			return fail(env, "check-ignore", stdinWithPathsError, exitError)
+		}
+
+		var err error
+		if targets, err = readPaths(env.stdin, opts.nul); err != nil {
+			return fail(env, "check-ignore", err, exitError)
+		}
+	} else if len(targets) == 0 {
+		return fail(env, "check-ignore", noPathsError, exitError)
+	}
+
+	root, err := checkIgnoreRoot(*file)
+	if err != nil {
+		return fail(env, "check-ignore", err, exitError)
+	}
+
+	name := filepath.Base(*file)
+	set, err := gignore.LoadIgnoreFileSet(os.DirFS(root), name)
+	if err != nil {
+		return fail(env, "check-ignore", err, exitError)
+	}
+
+	matches := make([]gignore.Match, 0, len(targets))
+	for _, target := range targets {
+		rel, err := relativeToFile(filepath.Join(root, name), target)
+		if err != nil {
+			return fail(env, "check-ignore", fmt.Errorf("%s: %w", target, err), exitError)
+		}
+
+		matches = append(matches, set.Match(rel))
+	}
+
+	tracked := make(map[string]bool)
+	if !*noIndex {
+		if tracked, err = trackedTargets(targets); err != nil {
+			return fail(env, "check-ignore", err, exitError)
+		}
+	}
+
+	code := exitNoMatch
+	for idx, match := range matches {
+		match.Path = targets[idx] // report paths the way they were given
+
+		// Like git, tracked paths are not subject to ignore rules
+		if tracked[targets[idx]] {
+			match.Rule = nil
+		}
+
+		// Like git, negated rules only count as a match in verbose mode
+		matched := match.Ignored() || (opts.verbose && match.Matched())
+		if matched {
+			code = exitOK
+		}
+
+		if matched || opts.nonMatching {
+			writeMatch(env.stdout, match, opts)
+		}
+	}
+
+	return code
+}
+
+// Returns the directory the ignore files are loaded from: the root of the git repository containing
+// the ignore file, or the ignore file's directory outside of a repository
+func checkIgnoreRoot(file string) (string, error) {
+	root, _, err := findRepository(filepath.Dir(file))
+	if errors.Is(err, notInRepositoryError) {
+		return filepath.Abs(filepath.Dir(file))
+	}
+
+	return root, err
+}
+
+// Returns which targets are tracked in the git repository containing the working directory.
+// Outside of a repository nothing is tracked
+func trackedTargets(targets []string) (map[string]bool, error) {
+	result := make(map[string]bool)
+
+	root, tracked, err := loadTracked(".")
+	if errors.Is(err, notInRepositoryError) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]bool, len(tracked))
+	for _, path := range tracked {
+		index[path] = true
+	}
+
+	for _, target := range targets {
+		abs, err := filepath.Abs(target)
+		if err != nil {
+			return nil, err
+		}
+
+		if rel, err := filepath.Rel(root, abs); err == nil && index[filepath.ToSlash(rel)] {
+			result[target] = true
+		}
+	}
+
+	return result, nil
+}
+
+func readPaths(reader io.Reader, nul bool) ([]string, error) {
+	scanner := bufio.NewScanner(reader)
+	if nul {
+		scanner.Split(splitNul)
+	}
+
+	var paths []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !nul {
+			line = strings.TrimSuffix(line, "\r")
+
+			// Like git, lines may be quoted the way paths are written without -z
+			if unquoted, err := strconv.Unquote(line); err == nil && strings.HasPrefix(line, `"`) {
+				line = unquoted
+			}
+		}
+
+		if line != "" {
+			paths = append(paths, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, stdinReadError
+	}
+
+	return paths, nil
+}
+
+func splitNul(data []byte, atEOF bool) (int, []byte, error) {
+	if idx := bytes.IndexByte(data, 0); idx != -1 {
+		return idx + 1, data[:idx], nil
+	}
+
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
+
+// Converts a path given relative to the working directory into a path relative to the
+// directory of the ignore file, with a trailing "/" if it is an existing directory
+func relativeToFile(file, target string) (string, error) {
+	base, err := filepath.Abs(filepath.Dir(file))
+	if err != nil {
+		return "", err
+	}
+
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(base, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", pathOutsideFileError
+	}
+
+	rel = filepath.ToSlash(rel)
+	if info, err := os.Stat(target); (err == nil && info.IsDir()) || strings.HasSuffix(target, "/") {
+		rel += "/"
+	}
+
+	return rel, nil
+}
+
+func writeMatch(w io.Writer, match gignore.Match, opts checkIgnoreOptions) {
+	if !opts.nul {
+		match.Path = quotePath(match.Path)
+	}
+
+	switch {
+	case opts.verbose && opts.nul:
+		fields := []string{"", "", "", match.Path}
+		if match.Matched() {
+			fields = []string{match.Source, strconv.Itoa(match.Line), match.Rule.Render(), match.Path}
+		}
+
+		fmt.Fprint(w, strings.Join(fields, "\x00")+"\x00")
+	case opts.verbose:
+		fmt.Fprintln(w, match.Log())
+	case opts.nul:
+		fmt.Fprint(w, match.Path+"\x00")
+	default:
+		fmt.Fprintln(w, match.Path)
+	}
+}
+
+// Escapes of the control characters git writes with a letter
+var cEscapes = map[byte]string{
+	'\a': `\a`,
+	'\b': `\b`,
+	'\t': `\t`,
+	'\n': `\n`,
+	'\v': `\v`,
+	'\f': `\f`,
+	'\r': `\r`,
+}
+
+// Quotes a path the way git does with core.quotePath, its default: a path containing a control
+// character, '"', '\' or a byte outside of ASCII is written between double quotes, with those
+// characters escaped C-style and other bytes as three octal digits. Other paths are returned as is
+//
+// Example: "caf\303\251\tmenu.txt" for "café<TAB>menu.txt"
+func quotePath(p string) string {
+	var quoted strings.Builder
+	needsQuotes := false
+
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+
+		switch {
+		case c == '"' || c == '\\':
+			quoted.WriteByte('\\')
+			quoted.WriteByte(c)
+			needsQuotes = true
+		case c < 0x20 || c >= 0x7f:
+			if escape, ok := cEscapes[c]; ok {
+				quoted.WriteString(escape)
+			} else {
+				fmt.Fprintf(&quoted, "\\%03o", c)
+			}
+			needsQuotes = true
+		default:
+			quoted.WriteByte(c)
+		}
+	}
+
+	if !needsQuotes {
+		return p
+	}
+
+	return `"` + quoted.String() + `"`
+}
@@ -0,0 +1,967 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MoonMoon1919/gignore"
+)
+
+var (
+	missingArgumentsError = errors.New("missing arguments")
+	tooManyArgumentsError = errors.New("too many arguments")
+	unknownRuleKindError  = errors.New("rule kind must be one of file, ext, dir or glob")
+	fileExistsError       = errors.New("file already exists, use -force to overwrite it")
+	unknownFormatError    = errors.New("unknown output format")
+)
+
+// MARK: Helpers
+func newFlagSet(name string, env environment) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet("gignore "+name, flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	file := flags.String("file", ".gitignore", "path to the ignore file")
+
+	return flags, file
+}
+
+// Parses flags and returns the exit code to use if the command should stop
+func parseFlags(flags *flag.FlagSet, args []string) (int, bool) {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+
+		return exitError, false
+	}
+
+	return exitOK, true
+}
+
+// Files are written back with the line endings and byte order mark they were read with
+var renderOptions = gignore.RenderOptions{
+	TrailingNewLine:       true,
+	LineEnding:            gignore.PRESERVE_LINE_ENDING,
+	PreserveByteOrderMark: true,
+}
+
+func newService() gignore.Service {
+	repo := gignore.NewFileRepository(renderOptions)
+
+	return gignore.NewService(repo)
+}
+
+func printResults(env environment, results []gignore.Result) {
+	printResultsAt(env, results, false)
+}
+
+// Like printResults, prefixing each result with the file and line of its rule if positions is set
+func printResultsAt(env environment, results []gignore.Result, positions bool) {
+	for _, result := range results {
+		if result.Rule == nil {
+			continue // nothing happened
+		}
+
+		if positions {
+			fmt.Fprintln(env.stdout, positioned(result.Position, result.Log()))
+			continue
+		}
+
+		fmt.Fprintln(env.stdout, result.Log())
+	}
+}
+
+// Prints a value as indented JSON
+func printJSON(env environment, value any) error {
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(env.stdout, string(encoded))
+
+	return nil
+}
+
+// Prints results as a JSON array, leaving out operations that did nothing like printResults
+func printResultsJSON(env environment, results []gignore.Result) error {
+	changed := make([]gignore.Result, 0, len(results))
+	for _, result := range results {
+		if result.Rule != nil {
+			changed = append(changed, result)
+		}
+	}
+
+	return printJSON(env, changed)
+}
+
+// Returns unknownFormatError unless format is one of the formats a command supports
+func checkFormat(format string, supported ...string) error {
+	for _, name := range supported {
+		if format == name {
+			return nil
+		}
+	}
+
+	return unknownFormatError
+}
+
+// Prefixes a finding with the position of its rule, like compiler output: "file:line: finding".
+// Findings about rules that were not parsed from the file are returned as they are
+func positioned(position gignore.Position, log string) string {
+	if !position.IsValid() {
+		return log
+	}
+
+	return position.String() + ": " + log
+}
+
+// Returns the position of the rule a conflict is reported at: the later rule, which is the one
+// a fix usually changes
+func conflictPosition(conflict gignore.Conflict) gignore.Position {
+	if conflict.RightPosition.IsValid() {
+		return conflict.RightPosition
+	}
+
+	return conflict.LeftPosition
+}
+
+// Returns exitConflicts for errors caused by conflicting rules and exitError for everything else
+func errorCode(err error) int {
+	if gignore.IsConflictError(err) {
+		return exitConflicts
+	}
+
+	return exitError
+}
+
+type ruleFlags struct {
+	action *string
+	mode   *string
+}
+
+func addRuleFlags(flags *flag.FlagSet) ruleFlags {
+	return ruleFlags{
+		action: flags.String("action", "include", "rule action: include (ignore) or exclude (negate)"),
+		mode:   flags.String("mode", "directory", "directory mode for dir rules: directory, children, recursive, anywhere or root_only"),
+	}
+}
+
+func (r ruleFlags) parse(kind string) (gignore.Action, gignore.DirectoryMode, error) {
+	action, err := gignore.ActionFromString(*r.action)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if kind != "dir" {
+		return action, 0, nil
+	}
+
+	mode, err := gignore.DirectoryModeFromString(*r.mode)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return action, mode, nil
+}
+
+// Arguments shared by add and rm: "<kind> [flags] <pattern>"
+type ruleCommand struct {
+	kind    string
+	file    string
+	pattern string
+	action  gignore.Action
+	mode    gignore.DirectoryMode
+}
+
+func parseRuleCommand(name string, args []string, env environment, extra func(*flag.FlagSet)) (ruleCommand, int, bool) {
+	if len(args) == 0 {
+		return ruleCommand{}, fail(env, name, missingArgumentsError, exitError), false
+	}
+
+	kind := args[0]
+	switch kind {
+	case "file", "ext", "dir", "glob":
+	case "-h", "-help", "--help":
+		fmt.Fprintf(env.stderr, "Usage: gignore %s <file|ext|dir|glob> [flags] <pattern>\n", name)
+		return ruleCommand{}, exitOK, false
+	default:
+		return ruleCommand{}, fail(env, name, unknownRuleKindError, exitError), false
+	}
+
+	flags, file := newFlagSet(name+" "+kind, env)
+	rule := addRuleFlags(flags)
+	if extra != nil {
+		extra(flags)
+	}
+
+	if code, ok := parseFlags(flags, args[1:]); !ok {
+		return ruleCommand{}, code, false
+	}
+
+	switch {
+	case flags.NArg() < 1:
+		return ruleCommand{}, fail(env, name, missingArgumentsError, exitError), false
+	case flags.NArg() > 1:
+		return ruleCommand{}, fail(env, name, tooManyArgumentsError, exitError), false
+	}
+
+	action, mode, err := rule.parse(kind)
+	if err != nil {
+		return ruleCommand{}, fail(env, name, err, exitError), false
+	}
+
+	return ruleCommand{
+		kind:    kind,
+		file:    *file,
+		pattern: flags.Arg(0),
+		action:  action,
+		mode:    mode,
+	}, exitOK, true
+}
+
+// MARK: Commands
+func runInit(args []string, env environment) int {
+	flags, file := newFlagSet("init", env)
+	force := flags.Bool("force", false, "overwrite the file if it already exists")
+	templates := flags.String("templates", "", "directory of .gitignore templates to use instead of the embedded ones")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if _, err := os.Stat(*file); err == nil && !*force {
+		return fail(env, "init", fileExistsError, exitError)
+	}
+
+	svc := newService()
+	if err := svc.InitFromSource(*file, templateSource(*templates), flags.Args()...); err != nil {
+		return fail(env, "init", err, exitError)
+	}
+
+	return exitOK
+}
+
+// Returns the templates of a directory, or the embedded templates if no directory is given
+func templateSource(dir string) gignore.TemplateSource {
+	if dir == "" {
+		return gignore.EmbeddedTemplates()
+	}
+
+	return gignore.NewDirTemplateSource(dir)
+}
+
+func runDrift(args []string, env environment) int {
+	flags, file := newFlagSet("drift", env)
+	templates := flags.String("templates", "", "directory of .gitignore templates to compare with instead of the embedded ones")
+	upgrade := flags.Bool("upgrade", false, "merge upstream template changes into the file")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	svc := newService()
+	source := templateSource(*templates)
+
+	if *upgrade {
+		results, err := svc.Upgrade(*file, source)
+		if err != nil {
+			return fail(env, "drift", err, exitError)
+		}
+
+		printResults(env, results)
+
+		return exitOK
+	}
+
+	drift, err := svc.FindTemplateDrift(*file, source)
+	if err != nil {
+		return fail(env, "drift", err, exitError)
+	}
+
+	for _, d := range drift {
+		fmt.Fprintln(env.stdout, d.Log())
+	}
+
+	if len(drift) > 0 {
+		return exitConflicts
+	}
+
+	return exitOK
+}
+
+// Git runs the driver with the base, ours and theirs versions as temporary files and expects the
+// merged result in ours, with a non-zero exit code if the merge needs attention: when rules
+// conflict once merged, or when both sides changed the same part of the file differently
+func runMergeDriver(args []string, env environment) int {
+	flags := flag.NewFlagSet("gignore merge-driver", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	switch {
+	case flags.NArg() < 3:
+		return fail(env, "merge-driver", missingArgumentsError, exitError)
+	case flags.NArg() > 3:
+		return fail(env, "merge-driver", tooManyArgumentsError, exitError)
+	}
+
+	svc := newService()
+	conflicts, mergeConflicts, err := svc.Merge(flags.Arg(0), flags.Arg(1), flags.Arg(2))
+	if err != nil {
+		return fail(env, "merge-driver", err, exitError)
+	}
+
+	for _, conflict := range mergeConflicts {
+		fmt.Fprintln(env.stderr, conflict.Log())
+	}
+
+	for _, conflict := range conflicts {
+		fmt.Fprintln(env.stderr, conflict.Log())
+	}
+
+	if len(conflicts) > 0 || len(mergeConflicts) > 0 {
+		return exitConflicts
+	}
+
+	return exitOK
+}
+
+func runTemplates(args []string, env environment) int {
+	flags := flag.NewFlagSet("gignore templates", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	dir := flags.String("templates", "", "directory of .gitignore templates to list instead of the embedded ones")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if flags.NArg() > 1 {
+		return fail(env, "templates", tooManyArgumentsError, exitError)
+	}
+
+	names, err := gignore.SearchTemplates(templateSource(*dir), flags.Arg(0))
+	if err != nil {
+		return fail(env, "templates", err, exitError)
+	}
+
+	for _, name := range names {
+		fmt.Fprintln(env.stdout, name)
+	}
+
+	if len(names) == 0 {
+		return exitNoMatch
+	}
+
+	return exitOK
+}
+
+func runAdd(args []string, env environment) int {
+	var section *string
+	cmd, code, ok := parseRuleCommand("add", args, env, func(flags *flag.FlagSet) {
+		section = flags.String("section", "", "add the rule to the end of this section")
+	})
+	if !ok {
+		return code
+	}
+
+	rule, err := cmd.rule()
+	if err != nil {
+		return fail(env, "add", err, exitError)
+	}
+
+	svc := newService()
+
+	var results []gignore.Result
+	if *section != "" {
+		results, err = svc.AddToSection(cmd.file, *section, rule)
+	} else {
+		results, err = cmd.add(&svc)
+	}
+
+	if err != nil {
+		return fail(env, "add", err, errorCode(err))
+	}
+
+	printResults(env, results)
+
+	return exitOK
+}
+
+func (c ruleCommand) rule() (gignore.Ruler, error) {
+	switch c.kind {
+	case "file":
+		return gignore.NewFileRule(c.pattern, c.action)
+	case "ext":
+		return gignore.NewExtensionRule(c.pattern, c.action)
+	case "dir":
+		return gignore.NewDirectoryRule(c.pattern, c.mode, c.action)
+	case "glob":
+		return gignore.NewGlobRule(c.pattern, c.action)
+	default:
+		return nil, unknownRuleKindError
+	}
+}
+
+func (c ruleCommand) add(svc *gignore.Service) ([]gignore.Result, error) {
+	switch c.kind {
+	case "file":
+		return svc.AddFileRule(c.file, c.pattern, c.action)
+	case "ext":
+		return svc.AddExtensionRule(c.file, c.pattern, c.action)
+	case "dir":
+		return svc.AddDirectoryRule(c.file, c.pattern, c.mode, c.action)
+	case "glob":
+		return svc.AddGlobRule(c.file, c.pattern, c.action)
+	default:
+		return nil, unknownRuleKindError
+	}
+}
+
+func (c ruleCommand) remove(svc *gignore.Service) (gignore.Result, error) {
+	switch c.kind {
+	case "file":
+		return svc.DeleteFileRule(c.file, c.pattern, c.action)
+	case "ext":
+		return svc.DeleteExtensionRule(c.file, c.pattern, c.action)
+	case "dir":
+		return svc.DeleteDirectoryRule(c.file, c.pattern, c.mode, c.action)
+	case "glob":
+		return svc.DeleteGlobRule(c.file, c.pattern, c.action)
+	default:
+		return gignore.Result{}, unknownRuleKindError
+	}
+}
+
+func runRemove(args []string, env environment) int {
+	cmd, code, ok := parseRuleCommand("rm", args, env, nil)
+	if !ok {
+		return code
+	}
+
+	svc := newService()
+	result, err := cmd.remove(&svc)
+	if err != nil {
+		return fail(env, "rm", err, exitError)
+	}
+
+	printResults(env, []gignore.Result{result})
+
+	return exitOK
+}
+
+func runMove(args []string, env environment) int {
+	flags, file := newFlagSet("mv", env)
+	flags.Usage = func() {
+		fmt.Fprintln(env.stderr, "Usage: gignore mv [flags] <rule> <before|after> <target-rule>")
+		flags.PrintDefaults()
+	}
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	switch {
+	case flags.NArg() < 3:
+		return fail(env, "mv", missingArgumentsError, exitError)
+	case flags.NArg() > 3:
+		return fail(env, "mv", tooManyArgumentsError, exitError)
+	}
+
+	direction, err := gignore.MoveDirectionFromString(flags.Arg(1))
+	if err != nil {
+		return fail(env, "mv", err, exitError)
+	}
+
+	svc := newService()
+	result, err := svc.MoveRule(*file, flags.Arg(0), flags.Arg(2), direction)
+	if err != nil {
+		return fail(env, "mv", err, exitError)
+	}
+
+	printResults(env, []gignore.Result{result})
+
+	return exitOK
+}
+
+func runAnalyze(args []string, env environment) int {
+	flags, file := newFlagSet("analyze", env)
+	positions := flags.Bool("v", false, "prefix each conflict with the file and line of its rule")
+	format := flags.String("format", "text", "output format: text, json, or sarif for code scanning")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if err := checkFormat(*format, "text", "json", "sarif"); err != nil {
+		return fail(env, "analyze", err, exitError)
+	}
+
+	svc := newService()
+
+	if *format == "sarif" {
+		report, found, err := svc.AnalyzeConflictsSARIF(*file)
+		if err != nil {
+			return fail(env, "analyze", err, exitError)
+		}
+
+		fmt.Fprintln(env.stdout, string(report))
+		if found > 0 {
+			return exitConflicts
+		}
+
+		return exitOK
+	}
+
+	conflicts, err := svc.AnalyzeConflicts(*file)
+	if err != nil {
+		return fail(env, "analyze", err, exitError)
+	}
+
+	switch *format {
+	case "json":
+		if err := printJSON(env, append(make([]gignore.Conflict, 0, len(conflicts)), conflicts...)); err != nil {
+			return fail(env, "analyze", err, exitError)
+		}
+	default:
+		for _, conflict := range conflicts {
+			if *positions {
+				fmt.Fprintln(env.stdout, positioned(conflictPosition(conflict), conflict.Log()))
+				continue
+			}
+
+			fmt.Fprintln(env.stdout, conflict.Log())
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return exitConflicts
+	}
+
+	return exitOK
+}
+
+func runLint(args []string, env environment) int {
+	flags, file := newFlagSet("lint", env)
+	disable := flags.String("disable", "", "comma-separated list of checks to disable")
+	enable := flags.String("enable", "", "comma-separated list of checks to enable, disabling all others")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	linter := gignore.NewLinter()
+
+	if *enable != "" {
+		for _, id := range linter.Checks() {
+			linter.Disable(id)
+		}
+
+		if err := toggleChecks(*enable, linter.Enable); err != nil {
+			return fail(env, "lint", err, exitError)
+		}
+	}
+
+	if err := toggleChecks(*disable, linter.Disable); err != nil {
+		return fail(env, "lint", err, exitError)
+	}
+
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return fail(env, "lint", err, exitError)
+	}
+
+	diagnostics := linter.Lint(string(content))
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintf(env.stdout, "%s:%s\n", *file, diagnostic.Log())
+	}
+
+	if len(diagnostics) > 0 {
+		return exitConflicts
+	}
+
+	return exitOK
+}
+
+func toggleChecks(list string, toggle func(gignore.LintCheckID) error) error {
+	if list == "" {
+		return nil
+	}
+
+	for _, id := range strings.Split(list, ",") {
+		id = strings.TrimSpace(id)
+		if err := toggle(gignore.LintCheckID(id)); err != nil {
+			return fmt.Errorf("%s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+func runDead(args []string, env environment) int {
+	flags, file := newFlagSet("dead", env)
+	root := flags.String("root", "", "working tree the file applies to (default: the file's directory)")
+	fix := flags.Bool("fix", false, "remove the dead rules")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if *root == "" {
+		*root = filepath.Dir(*file)
+	}
+
+	svc := newService()
+	tree := os.DirFS(*root)
+
+	if *fix {
+		results, err := svc.RemoveDeadRules(*file, tree)
+		if err != nil {
+			return fail(env, "dead", err, exitError)
+		}
+
+		printResults(env, results)
+
+		return exitOK
+	}
+
+	deadRules, err := svc.FindDeadRules(*file, tree)
+	if err != nil {
+		return fail(env, "dead", err, exitError)
+	}
+
+	for _, deadRule := range deadRules {
+		fmt.Fprintln(env.stdout, deadRule.Log())
+	}
+
+	if len(deadRules) > 0 {
+		return exitConflicts
+	}
+
+	return exitOK
+}
+
+func runSuggest(args []string, env environment) int {
+	flags, file := newFlagSet("suggest", env)
+	root := flags.String("root", "", "directory to detect ecosystems in (default: the file's directory)")
+	apply := flags.Bool("apply", false, "add the missing rules")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if *root == "" {
+		*root = filepath.Dir(*file)
+	}
+
+	svc := newService()
+
+	suggestions, err := svc.SuggestEcosystemRules(*file, os.DirFS(*root))
+	if err != nil {
+		return fail(env, "suggest", err, exitError)
+	}
+
+	if *apply {
+		results, err := svc.ApplySuggestions(*file, suggestions)
+		if err != nil {
+			return fail(env, "suggest", err, exitError)
+		}
+
+		printResults(env, results)
+
+		return exitOK
+	}
+
+	for _, suggestion := range suggestions {
+		fmt.Fprintln(env.stdout, suggestion.Log())
+	}
+
+	if len(suggestions) > 0 {
+		return exitConflicts
+	}
+
+	return exitOK
+}
+
+func runTracked(args []string, env environment) int {
+	flags := flag.NewFlagSet("gignore tracked", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	dir := flags.String("C", ".", "run as if started in this directory")
+	name := flags.String("name", ".gitignore", "name of the ignore files to check")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	root, tracked, err := loadTracked(*dir)
+	if err != nil {
+		return fail(env, "tracked", err, exitError)
+	}
+
+	set, err := gignore.LoadIgnoreFileSet(os.DirFS(root), *name)
+	if err != nil {
+		return fail(env, "tracked", err, exitError)
+	}
+
+	matches := set.TrackedIgnored(tracked)
+	for _, match := range matches {
+		fmt.Fprintln(env.stdout, match.Log())
+	}
+
+	if len(matches) > 0 {
+		return exitConflicts
+	}
+
+	return exitOK
+}
+
+func runUntracked(args []string, env environment) int {
+	flags := flag.NewFlagSet("gignore untracked", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	dir := flags.String("C", ".", "run as if started in this directory")
+	name := flags.String("name", ".gitignore", "name of the ignore files to check")
+	suggest := flags.Bool("suggest", false, "print rules that would cover the untracked files instead of the files")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	root, tracked, err := loadTracked(*dir)
+	if err != nil {
+		return fail(env, "untracked", err, exitError)
+	}
+
+	tree := os.DirFS(root)
+	set, err := gignore.LoadIgnoreFileSet(tree, *name)
+	if err != nil {
+		return fail(env, "untracked", err, exitError)
+	}
+
+	untracked, err := set.Untracked(tree, tracked)
+	if err != nil {
+		return fail(env, "untracked", err, exitError)
+	}
+
+	if *suggest {
+		for _, suggestion := range gignore.SuggestRules(untracked, tracked) {
+			fmt.Fprintln(env.stdout, suggestion.Log())
+		}
+	} else {
+		for _, untrackedPath := range untracked {
+			fmt.Fprintln(env.stdout, untrackedPath)
+		}
+	}
+
+	if len(untracked) > 0 {
+		return exitConflicts
+	}
+
+	return exitOK
+}
+
+func runMinimize(args []string, env environment) int {
+	flags, file := newFlagSet("minimize", env)
+	positions := flags.Bool("v", false, "prefix each change with the file and line of its rule")
+	format := flags.String("format", "text", "output format: text or json")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if err := checkFormat(*format, "text", "json"); err != nil {
+		return fail(env, "minimize", err, exitError)
+	}
+
+	svc := newService()
+	results, err := svc.Minimize(*file)
+	if err != nil {
+		return fail(env, "minimize", err, exitError)
+	}
+
+	if *format == "json" {
+		if err := printResultsJSON(env, results); err != nil {
+			return fail(env, "minimize", err, exitError)
+		}
+	} else {
+		printResultsAt(env, results, *positions)
+	}
+
+	return exitOK
+}
+
+// Renders the policy document given as argument into -file, reading stdin for "-"
+func runImport(args []string, env environment) int {
+	flags, file := newFlagSet("import", env)
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	switch {
+	case flags.NArg() < 1:
+		return fail(env, "import", missingArgumentsError, exitError)
+	case flags.NArg() > 1:
+		return fail(env, "import", tooManyArgumentsError, exitError)
+	}
+
+	var data []byte
+	var err error
+	if flags.Arg(0) == "-" {
+		data, err = io.ReadAll(env.stdin)
+	} else {
+		data, err = os.ReadFile(flags.Arg(0))
+	}
+	if err != nil {
+		return fail(env, "import", err, exitError)
+	}
+
+	var policy gignore.Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return fail(env, "import", err, exitError)
+	}
+
+	svc := newService()
+	if err := svc.ImportPolicy(*file, policy); err != nil {
+		// Every invalid entry is reported on its own line
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fail(env, "import", errors.New(line), exitError)
+		}
+
+		return exitError
+	}
+
+	return exitOK
+}
+
+func runExport(args []string, env environment) int {
+	flags, file := newFlagSet("export", env)
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	svc := newService()
+	policy, err := svc.ExportPolicy(*file)
+	if err != nil {
+		return fail(env, "export", err, exitError)
+	}
+
+	if err := printJSON(env, policy); err != nil {
+		return fail(env, "export", err, exitError)
+	}
+
+	return exitOK
+}
+
+// Formats the files given as arguments, or -file without arguments. With -check the files are
+// only listed if they are not formatted, like gofmt -l
+func runFormat(args []string, env environment) int {
+	flags, file := newFlagSet("fmt", env)
+	check := flags.Bool("check", false, "report unformatted files instead of rewriting them")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{*file}
+	}
+
+	unformatted := false
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fail(env, "fmt", err, exitError)
+		}
+
+		formatted, err := gignore.Format(string(content), renderOptions)
+		if err != nil {
+			return fail(env, "fmt", fmt.Errorf("%s: %w", path, err), exitError)
+		}
+
+		warnUnformatted(env, path, string(content))
+
+		if formatted == string(content) {
+			continue
+		}
+
+		if *check {
+			fmt.Fprintln(env.stdout, path)
+			unformatted = true
+			continue
+		}
+
+		if err := os.WriteFile(path, []byte(formatted), 0o644); err != nil {
+			return fail(env, "fmt", err, exitError)
+		}
+	}
+
+	if unformatted {
+		return exitConflicts
+	}
+
+	return exitOK
+}
+
+// Warns about patterns with "./" segments. They never match, but fmt leaves them alone since
+// rewriting "./tmp" to "/tmp" would start ignoring tmp
+func warnUnformatted(env environment, path, content string) {
+	linter := gignore.NewLinter()
+	for _, id := range linter.Checks() {
+		if id != gignore.DOT_SLASH_PREFIX {
+			linter.Disable(id)
+		}
+	}
+
+	for _, diagnostic := range linter.Lint(content) {
+		fmt.Fprintf(env.stderr, "%s:%d: %s: pattern never matches and is left unchanged\n", path, diagnostic.Line, diagnostic.Check)
+	}
+}
+
+func runFix(args []string, env environment) int {
+	flags, file := newFlagSet("fix", env)
+	passes := flags.Int("passes", 10, "maximum number of conflict resolution passes")
+	positions := flags.Bool("v", false, "prefix each change with the file and line of its rule")
+	format := flags.String("format", "text", "output format: text or json")
+
+	if code, ok := parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if err := checkFormat(*format, "text", "json"); err != nil {
+		return fail(env, "fix", err, exitError)
+	}
+
+	svc := newService()
+	results, err := svc.AutoFix(*file, *passes)
+	if err != nil {
+		return fail(env, "fix", err, exitError)
+	}
+
+	if *format == "json" {
+		if err := printResultsJSON(env, results); err != nil {
+			return fail(env, "fix", err, exitError)
+		}
+	} else {
+		printResultsAt(env, results, *positions)
+	}
+
+	remaining, err := svc.AnalyzeConflicts(*file)
+	if err != nil {
+		return fail(env, "fix", err, exitError)
+	}
+
+	if len(remaining) > 0 {
+		return exitConflicts
+	}
+
+	return exitOK
+}
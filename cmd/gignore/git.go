@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MoonMoon1919/gignore"
+)
+
+var notInRepositoryError = errors.New("not inside a git repository")
+
+// Returns the root of the git repository containing dir and the path to its index
+func findRepository(dir string) (string, string, error) {
+	current, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	for {
+		gitPath := filepath.Join(current, ".git")
+
+		if info, err := os.Stat(gitPath); err == nil {
+			if info.IsDir() {
+				return current, filepath.Join(gitPath, "index"), nil
+			}
+
+			// Worktrees and submodules have a .git file pointing at their git directory
+			content, err := os.ReadFile(gitPath)
+			if err != nil {
+				return "", "", err
+			}
+
+			gitDir := strings.TrimSpace(strings.TrimPrefix(string(content), "gitdir:"))
+			if !filepath.IsAbs(gitDir) {
+				gitDir = filepath.Join(current, gitDir)
+			}
+
+			return current, filepath.Join(gitDir, "index"), nil
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return "", "", notInRepositoryError
+		}
+
+		current = parent
+	}
+}
+
+// Returns the root of the git repository containing dir and its tracked paths. A repository
+// without an index, such as a fresh "git init", has nothing tracked
+func loadTracked(dir string) (string, []string, error) {
+	root, indexPath, err := findRepository(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	file, err := os.Open(indexPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return root, []string{}, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+
+	tracked, err := gignore.ReadIndex(file)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return root, tracked, nil
+}
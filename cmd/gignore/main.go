@@ -0,0 +1,113 @@
+// Command gignore manages .gitignore style files from the command line.
+//
+// Usage:
+//
+//	gignore <command> [flags] [arguments]
+//
+// Most commands operate on the file given by -file (default ".gitignore"). Commands exit
+// with 0 on success, 1 when conflicts or other findings were found, and 2 on any other error,
+// so they can be used to gate CI pipelines. Like git, check-ignore exits with 1 when no path
+// matched.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// MARK: Exit codes
+const (
+	exitOK        = 0
+	exitConflicts = 1
+	exitNoMatch   = 1
+	exitError     = 2
+)
+
+type environment struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, env environment) int
+}
+
+func commands() []command {
+	return []command{
+		{name: "init", summary: "Create an ignore file, empty or composed from templates", run: runInit},
+		{name: "templates", summary: "List or search the available templates", run: runTemplates},
+		{name: "drift", summary: "Report or merge changes between the file and its templates", run: runDrift},
+		{name: "merge-driver", summary: "Merge three versions of the file rule by rule, for use as a git merge driver", run: runMergeDriver},
+		{name: "import", summary: "Render a JSON policy document into the file, - reads stdin", run: runImport},
+		{name: "export", summary: "Print the file as a JSON policy document", run: runExport},
+		{name: "add", summary: "Add a file, ext, dir or glob rule", run: runAdd},
+		{name: "rm", summary: "Remove a file, ext, dir or glob rule", run: runRemove},
+		{name: "mv", summary: "Move a rule before or after another rule", run: runMove},
+		{name: "analyze", summary: "Report conflicts without modifying the file", run: runAnalyze},
+		{name: "fix", summary: "Automatically fix conflicts", run: runFix},
+		{name: "minimize", summary: "Shrink the rule set without changing what it ignores", run: runMinimize},
+		{name: "fmt", summary: "Rewrite files in canonical form, or list unformatted files with -check", run: runFormat},
+		{name: "suggest", summary: "Detect project ecosystems and report or add their standard rules", run: runSuggest},
+		{name: "dead", summary: "Report or remove rules that match nothing in the working tree", run: runDead},
+		{name: "tracked", summary: "Report tracked files that are ignored, reading the git index", run: runTracked},
+		{name: "untracked", summary: "Report untracked files no rule covers and suggest rules for them", run: runUntracked},
+		{name: "lint", summary: "Report patterns that likely don't do what they look like", run: runLint},
+		{name: "check-ignore", summary: "Show which rule ignores each path, like git check-ignore", run: runCheckIgnore},
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: gignore <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'gignore <command> -h' for the flags of a command.")
+}
+
+func run(args []string, env environment) int {
+	if len(args) == 0 {
+		usage(env.stderr)
+		return exitError
+	}
+
+	name := args[0]
+	if name == "-h" || name == "-help" || name == "--help" || name == "help" {
+		usage(env.stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return cmd.run(args[1:], env)
+		}
+	}
+
+	fmt.Fprintf(env.stderr, "gignore: unknown command %q\n\n", name)
+	usage(env.stderr)
+
+	return exitError
+}
+
+func main() {
+	env := environment{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+
+	os.Exit(run(os.Args[1:], env))
+}
+
+// Prints an error prefixed with the command name and returns the matching exit code
+func fail(env environment, name string, err error, code int) int {
+	fmt.Fprintf(env.stderr, "gignore %s: %s\n", name, strings.TrimSpace(err.Error()))
+	return code
+}
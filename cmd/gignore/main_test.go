@@ -0,0 +1,919 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MoonMoon1919/gignore"
+)
+
+// Runs the CLI with the given arguments and returns the exit code and output
+func runCLI(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(args, environment{
+		stdin:  strings.NewReader(stdin),
+		stdout: &stdout,
+		stderr: &stderr,
+	})
+
+	return code, stdout.String(), stderr.String()
+}
+
+func writeIgnoreFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("unexpected error writing file: %s", err.Error())
+	}
+
+	return path
+}
+
+func readIgnoreFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading file: %s", err.Error())
+	}
+
+	return string(content)
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		args     []string
+		code     int
+		stdout   string
+		expected string
+	}{
+		{
+			name:     "Pass-AddExtension",
+			content:  "",
+			args:     []string{"add", "ext", "-file=FILE", "log"},
+			code:     exitOK,
+			stdout:   "ADDED: Rule '*.log', Reason: REQUESTED\n",
+			expected: "*.log\n",
+		},
+		{
+			name:     "Pass-AddDirectoryWithMode",
+			content:  "",
+			args:     []string{"add", "dir", "-file=FILE", "-mode", "recursive", "build"},
+			code:     exitOK,
+			stdout:   "ADDED: Rule 'build/**', Reason: REQUESTED\n",
+			expected: "build/**\n",
+		},
+		{
+			name:     "Pass-AddToSection",
+			content:  "# Go\n*.test\n",
+			args:     []string{"add", "dir", "-file=FILE", "-section", "IDE", ".idea"},
+			code:     exitOK,
+			stdout:   "ADDED: Rule '.idea/', Reason: REQUESTED\n",
+			expected: "# Go\n*.test\n\n# IDE\n.idea/\n",
+		},
+		{
+			name:     "Pass-AddFileWithGlobCharacterToSection",
+			content:  "# Go\n*.test\n",
+			args:     []string{"add", "file", "-file=FILE", "-section", "Drafts", "draft*.md"},
+			code:     exitOK,
+			stdout:   "ADDED: Rule 'draft*.md', Reason: REQUESTED\n",
+			expected: "# Go\n*.test\n\n# Drafts\ndraft*.md\n",
+		},
+		{
+			name:     "Fail-AddConflict",
+			content:  "*.log\n",
+			args:     []string{"add", "file", "-file=FILE", "debug.log"},
+			code:     exitConflicts,
+			expected: "*.log\n",
+		},
+		{
+			name:     "Fail-InvalidAction",
+			content:  "",
+			args:     []string{"add", "file", "-file=FILE", "-action", "maybe", "todo.md"},
+			code:     exitError,
+			expected: "",
+		},
+		{
+			name:     "Fail-InvalidKind",
+			content:  "",
+			args:     []string{"add", "folder", "-file=FILE", "build"},
+			code:     exitError,
+			expected: "",
+		},
+		{
+			name:     "Pass-Remove",
+			content:  "todo.md\n*.log\n",
+			args:     []string{"rm", "file", "-file=FILE", "todo.md"},
+			code:     exitOK,
+			stdout:   "REMOVED: Rule 'todo.md', Reason: REQUESTED\n",
+			expected: "*.log\n",
+		},
+		{
+			name:     "Fail-RemoveMissing",
+			content:  "*.log\n",
+			args:     []string{"rm", "file", "-file=FILE", "todo.md"},
+			code:     exitError,
+			expected: "*.log\n",
+		},
+		{
+			name:     "Pass-Move",
+			content:  "!build/important.txt\nbuild/**\n",
+			args:     []string{"mv", "-file=FILE", "!build/important.txt", "after", "build/**"},
+			code:     exitOK,
+			stdout:   "MOVED: Rule '!build/important.txt', Reason: REQUESTED\n",
+			expected: "build/**\n!build/important.txt\n",
+		},
+		{
+			name:     "Fail-MoveInvalidDirection",
+			content:  "!build/important.txt\nbuild/**\n",
+			args:     []string{"mv", "-file=FILE", "!build/important.txt", "under", "build/**"},
+			code:     exitError,
+			expected: "!build/important.txt\nbuild/**\n",
+		},
+		{
+			name:     "Pass-AnalyzeClean",
+			content:  "*.log\n",
+			args:     []string{"analyze", "-file=FILE"},
+			code:     exitOK,
+			expected: "*.log\n",
+		},
+		{
+			name:     "Fail-AnalyzeConflicts",
+			content:  "*.log\n*.log\n",
+			args:     []string{"analyze", "-file=FILE"},
+			code:     exitConflicts,
+			stdout:   "REDUNDANT_RULE: Rules '*.log' and '*.log'\n",
+			expected: "*.log\n*.log\n",
+		},
+		{
+			name:     "Pass-Fix",
+			content:  "*.log\n*.log\n",
+			args:     []string{"fix", "-file=FILE"},
+			code:     exitOK,
+			stdout:   "REMOVED: Rule '*.log', Reason: AUTOMATED_FIX\n",
+			expected: "*.log\n",
+		},
+		{
+			name:     "Fail-FixSemanticConflict",
+			content:  "todo.md\n!todo.md\n",
+			args:     []string{"fix", "-file=FILE", "-passes", "1"},
+			code:     exitConflicts,
+			stdout:   "REVIEW_RECOMMENDED: Rule 'todo.md', Reason: FIX_UNKNOWN\n",
+			expected: "todo.md\n!todo.md\n",
+		},
+		{
+			name:     "Pass-Minimize",
+			content:  "logs/**\nlogs/*.log\n",
+			args:     []string{"minimize", "-file=FILE"},
+			code:     exitOK,
+			stdout:   "REMOVED: Rule 'logs/*.log', Reason: MINIMIZED\n",
+			expected: "logs/**\n",
+		},
+		{
+			name:     "Pass-KeepsCRLF",
+			content:  "*.log\r\n",
+			args:     []string{"add", "dir", "-file=FILE", "build"},
+			code:     exitOK,
+			expected: "*.log\r\nbuild/\r\n",
+		},
+		{
+			name:     "Fail-UnknownCommand",
+			content:  "",
+			args:     []string{"frobnicate"},
+			code:     exitError,
+			expected: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeIgnoreFile(t, tc.content)
+
+			args := make([]string, len(tc.args))
+			for i, arg := range tc.args {
+				args[i] = strings.ReplaceAll(arg, "FILE", path)
+			}
+
+			code, stdout, stderr := runCLI(t, "", args...)
+			if code != tc.code {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tc.code, code, stderr)
+			}
+
+			if tc.stdout != "" && stdout != tc.stdout {
+				t.Errorf("expected output %q, got %q", tc.stdout, stdout)
+			}
+
+			if content := readIgnoreFile(t, path); content != tc.expected {
+				t.Errorf("expected file %q, got %q", tc.expected, content)
+			}
+		})
+	}
+}
+
+func TestRunInit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gitignore")
+
+	if code, _, stderr := runCLI(t, "", "init", "-file", path); code != exitOK {
+		t.Errorf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+	}
+
+	if code, _, _ := runCLI(t, "", "init", "-file", path); code != exitError {
+		t.Errorf("expected init to refuse to overwrite an existing file")
+	}
+
+	if code, _, _ := runCLI(t, "", "init", "-file", path, "-force"); code != exitOK {
+		t.Errorf("expected init -force to overwrite an existing file")
+	}
+
+	if code, _, stderr := runCLI(t, "", "init", "-file", path, "-force", "rust"); code != exitOK {
+		t.Errorf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+	}
+
+	if expected := "\n\n# Rust\n*.pdb\n*.rs.bk\ntarget/\n"; !strings.HasSuffix(readIgnoreFile(t, path), expected) {
+		t.Errorf("expected file to end with %q, got %q", expected, readIgnoreFile(t, path))
+	}
+
+	// Composed files are already in canonical form
+	if code, _, stderr := runCLI(t, "", "init", "-file", path, "-force", "go", "node", "jetbrains", "macos"); code != exitOK {
+		t.Errorf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+	}
+
+	if code, stdout, _ := runCLI(t, "", "fmt", "-check", "-file", path); code != exitOK || stdout != "" {
+		t.Errorf("expected init output to pass fmt -check, got %d: %q", code, stdout)
+	}
+
+	if code, stdout, _ := runCLI(t, "", "drift", "-file", path); code != exitOK || stdout != "" {
+		t.Errorf("expected no drift right after init, got %d: %q", code, stdout)
+	}
+
+	if code, _, _ := runCLI(t, "", "init", "-file", path, "-force", "cobol"); code != exitError {
+		t.Errorf("expected init to fail for an unknown template")
+	}
+}
+
+func TestRunTemplates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Company.gitignore"), []byte("*.secret\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if code, stdout, _ := runCLI(t, "", "templates", "j"); code != exitOK || stdout != "java\njetbrains\n" {
+		t.Errorf("expected java and jetbrains to be found, got %d: %q", code, stdout)
+	}
+
+	if code, stdout, _ := runCLI(t, "", "templates", "-templates", dir); code != exitOK || stdout != "Company\n" {
+		t.Errorf("expected the directory's templates to be listed, got %d: %q", code, stdout)
+	}
+
+	if code, _, _ := runCLI(t, "", "templates", "cobol"); code != exitNoMatch {
+		t.Errorf("expected exit code %d when no template matches, got %d", exitNoMatch, code)
+	}
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+	if code, _, stderr := runCLI(t, "", "init", "-file", path, "-templates", dir, "company"); code != exitOK {
+		t.Errorf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+	}
+
+	if expected := "# gignore:template Company "; !strings.HasPrefix(readIgnoreFile(t, path), expected) {
+		t.Errorf("expected file to start with %q, got %q", expected, readIgnoreFile(t, path))
+	}
+}
+
+func TestRunDrift(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "company.gitignore")
+	if err := os.WriteFile(template, []byte("*.secret\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	path := filepath.Join(t.TempDir(), ".gitignore")
+	if code, _, stderr := runCLI(t, "", "init", "-file", path, "-templates", dir, "company"); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+	}
+
+	if code, stdout, _ := runCLI(t, "", "drift", "-file", path, "-templates", dir); code != exitOK || stdout != "" {
+		t.Errorf("expected no drift, got %d: %q", code, stdout)
+	}
+
+	if err := os.WriteFile(template, []byte("*.secret\n*.swp\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	code, stdout, _ := runCLI(t, "", "drift", "-file", path, "-templates", dir)
+	if code != exitConflicts || stdout != "ADDED_UPSTREAM: Rule '*.swp', Template: company\n" {
+		t.Errorf("expected *.swp to be reported with exit code %d, got %d: %q", exitConflicts, code, stdout)
+	}
+
+	code, stdout, _ = runCLI(t, "", "drift", "-file", path, "-templates", dir, "-upgrade")
+	if code != exitOK || stdout != "ADDED: Rule '*.swp', Reason: UPGRADED\n" {
+		t.Errorf("expected *.swp to be added, got %d: %q", code, stdout)
+	}
+
+	if !strings.HasSuffix(readIgnoreFile(t, path), "# company\n*.secret\n*.swp\n") {
+		t.Errorf("expected *.swp in the company section, got %q", readIgnoreFile(t, path))
+	}
+}
+
+func TestRunMergeDriver(t *testing.T) {
+	base := writeIgnoreFile(t, "*.log\nbuild/\n")
+	ours := writeIgnoreFile(t, "*.log\nbuild/\n.env\n")
+	theirs := writeIgnoreFile(t, "*.log\n*.tmp\n")
+
+	if code, _, stderr := runCLI(t, "", "merge-driver", base, ours, theirs); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+	}
+
+	if merged := readIgnoreFile(t, ours); merged != "*.log\n.env\n*.tmp\n" {
+		t.Errorf("expected the merge in ours, got %q", merged)
+	}
+
+	// The negation ours adds comes before the rule theirs adds, so it no longer has an effect
+	ours = writeIgnoreFile(t, "*.log\nbuild/\n!debug.txt\n")
+	theirs = writeIgnoreFile(t, "*.log\nbuild/\n*.txt\n")
+
+	code, _, stderr := runCLI(t, "", "merge-driver", base, ours, theirs)
+	if code != exitConflicts || stderr == "" {
+		t.Errorf("expected conflicts to be reported with exit code %d, got %d: %q", exitConflicts, code, stderr)
+	}
+
+	// A conflict ours already had does not fail a clean merge
+	base = writeIgnoreFile(t, "*.log\n")
+	ours = writeIgnoreFile(t, "*.log\n!*.log\n")
+	theirs = writeIgnoreFile(t, "*.log\n.idea/\n")
+
+	if code, _, stderr := runCLI(t, "", "merge-driver", base, ours, theirs); code != exitOK || stderr != "" {
+		t.Errorf("expected exit code %d without conflicts, got %d: %q", exitOK, code, stderr)
+	}
+
+	if merged := readIgnoreFile(t, ours); merged != "*.log\n!*.log\n.idea/\n" {
+		t.Errorf("expected the merge in ours, got %q", merged)
+	}
+
+	// Comments, the footer and the lines around a managed block are merged too
+	base = writeIgnoreFile(t, "# BEGIN gignore:managed\n*.log\nbuild/\n# END gignore:managed\n")
+	ours = writeIgnoreFile(t, "# BEGIN gignore:managed\n*.log\nbuild/\n.env\n# END gignore:managed\n")
+	theirs = writeIgnoreFile(t, "# BEGIN gignore:managed\n*.log\n# Output of make\nbuild/\n\n# Keep this block sorted\n# END gignore:managed\nlocal/\n")
+
+	if code, _, stderr := runCLI(t, "", "merge-driver", base, ours, theirs); code != exitOK || stderr != "" {
+		t.Errorf("expected exit code %d without conflicts, got %d: %q", exitOK, code, stderr)
+	}
+
+	expected := "# BEGIN gignore:managed\n*.log\n# Output of make\nbuild/\n.env\n\n# Keep this block sorted\n# END gignore:managed\nlocal/\n"
+	if merged := readIgnoreFile(t, ours); merged != expected {
+		t.Errorf("expected %q, got %q", expected, merged)
+	}
+
+	// Changes both sides made differently are left between conflict markers
+	base = writeIgnoreFile(t, "*.log\n")
+	ours = writeIgnoreFile(t, "*.log\n\n# ours\n")
+	theirs = writeIgnoreFile(t, "*.log\n\n# theirs\n")
+
+	code, _, stderr = runCLI(t, "", "merge-driver", base, ours, theirs)
+	if code != exitConflicts || stderr != "MERGE_CONFLICT: footer changed on both sides\n" {
+		t.Errorf("expected the footer conflict with exit code %d, got %d: %q", exitConflicts, code, stderr)
+	}
+
+	if merged := readIgnoreFile(t, ours); merged != "*.log\n\n<<<<<<< ours\n# ours\n=======\n# theirs\n>>>>>>> theirs\n" {
+		t.Errorf("expected conflict markers in ours, got %q", merged)
+	}
+
+	if code, _, _ := runCLI(t, "", "merge-driver", base, ours); code != exitError {
+		t.Errorf("expected exit code %d for missing arguments, got %d", exitError, code)
+	}
+}
+
+func TestRunPositions(t *testing.T) {
+	path := writeIgnoreFile(t, "*.log\nbuild/**\nbuild/\n")
+
+	code, stdout, stderr := runCLI(t, "", "analyze", "-file", path, "-v")
+	if code != exitConflicts {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitConflicts, code, stderr)
+	}
+
+	if expected := path + ":3: UNREACHABLE_RULE: Rules 'build/**' and 'build/'\n"; stdout != expected {
+		t.Errorf("expected %q, got %q", expected, stdout)
+	}
+
+	if code, stdout, stderr = runCLI(t, "", "fix", "-file", path, "-v"); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+	}
+
+	if expected := path + ":3: REMOVED: Rule 'build/', Reason: AUTOMATED_FIX\n"; stdout != expected {
+		t.Errorf("expected %q, got %q", expected, stdout)
+	}
+}
+
+func TestRunAnalyzeSARIF(t *testing.T) {
+	path := writeIgnoreFile(t, "*.log\nbuild/**\nbuild/\n")
+
+	code, stdout, stderr := runCLI(t, "", "analyze", "-file", path, "-format", "sarif")
+	if code != exitConflicts {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitConflicts, code, stderr)
+	}
+
+	var report struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID  string `json:"ruleId"`
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("expected a JSON report, got %q: %s", stdout, err.Error())
+	}
+
+	if report.Version != "2.1.0" || len(report.Runs) != 1 || len(report.Runs[0].Results) != 1 {
+		t.Fatalf("expected one SARIF 2.1.0 result, got %+v", report)
+	}
+
+	if ruleID := report.Runs[0].Results[0].RuleID; ruleID != "UNREACHABLE_RULE" {
+		t.Errorf("expected rule UNREACHABLE_RULE, got %s", ruleID)
+	}
+
+	if message := report.Runs[0].Results[0].Message.Text; !strings.HasPrefix(message, "UNREACHABLE_RULE: Rules 'build/**' and 'build/'") {
+		t.Errorf("expected the message to describe the conflict, got %q", message)
+	}
+
+	if code, _, _ := runCLI(t, "", "analyze", "-file", path, "-format", "xml"); code != exitError {
+		t.Errorf("expected exit code %d for an unknown format, got %d", exitError, code)
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	path := writeIgnoreFile(t, "*.log\nbuild/**\nbuild/\n")
+
+	code, stdout, stderr := runCLI(t, "", "analyze", "-file", path, "-format", "json")
+	if code != exitConflicts {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitConflicts, code, stderr)
+	}
+
+	var conflicts []gignore.Conflict
+	if err := json.Unmarshal([]byte(stdout), &conflicts); err != nil {
+		t.Fatalf("expected JSON conflicts, got %q: %s", stdout, err.Error())
+	}
+
+	if len(conflicts) != 1 || conflicts[0].ConflictType != gignore.UNREACHABLE_RULE || conflicts[0].RightPosition.Line != 3 {
+		t.Errorf("expected the unreachable rule on line 3, got %+v", conflicts)
+	}
+
+	if code, stdout, stderr = runCLI(t, "", "fix", "-file", path, "-format", "json"); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+	}
+
+	var results []gignore.Result
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+		t.Fatalf("expected JSON results, got %q: %s", stdout, err.Error())
+	}
+
+	if len(results) != 1 || results[0].Log() != "REMOVED: Rule 'build/', Reason: AUTOMATED_FIX" {
+		t.Errorf("expected build/ to be removed, got %+v", results)
+	}
+
+	if code, stdout, _ = runCLI(t, "", "analyze", "-file", path, "-format", "json"); code != exitOK || strings.TrimSpace(stdout) != "[]" {
+		t.Errorf("expected an empty array once fixed, got %d: %q", code, stdout)
+	}
+
+	if code, _, _ := runCLI(t, "", "fix", "-file", path, "-format", "sarif"); code != exitError {
+		t.Errorf("expected exit code %d for an unsupported format, got %d", exitError, code)
+	}
+}
+
+func TestRunPolicy(t *testing.T) {
+	source := writeIgnoreFile(t, "# Logs\n*.log\nbuild/**\n")
+
+	code, policy, stderr := runCLI(t, "", "export", "-file", source)
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+	}
+
+	target := filepath.Join(t.TempDir(), ".gitignore")
+	if code, _, stderr := runCLI(t, policy, "import", "-file", target, "-"); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+	}
+
+	if content := readIgnoreFile(t, target); content != "# Logs\n*.log\nbuild/**\n" {
+		t.Errorf("expected the exported rules, got %q", content)
+	}
+
+	invalid := `{"rules": [{"kind": "dir", "pattern": "build", "mode": "sideways"}, {"kind": "file", "pattern": ""}]}`
+	code, _, stderr = runCLI(t, invalid, "import", "-file", target, "-")
+	if code != exitError {
+		t.Errorf("expected exit code %d, got %d", exitError, code)
+	}
+
+	expected := "gignore import: rules[0].mode: invalid directory mode\ngignore import: rules[1].pattern: path cannot be empty\n"
+	if stderr != expected {
+		t.Errorf("expected %q, got %q", expected, stderr)
+	}
+}
+
+func TestRunCheckIgnore(t *testing.T) {
+	path := writeIgnoreFile(t, "*.log\n!keep.log\nbuild/\n")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := os.Chdir(filepath.Dir(path)); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	if err := os.Mkdir("build", 0o755); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if err := os.Mkdir("a", 0o755); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := os.WriteFile(filepath.Join("a", ".gitignore"), []byte("# text files\n*.txt\n!b.log\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	tests := []struct {
+		name   string
+		stdin  string
+		args   []string
+		code   int
+		stdout string
+	}{
+		{
+			name:   "Pass-Quiet",
+			args:   []string{"check-ignore", "debug.log", "keep.log", "main.go"},
+			code:   exitOK,
+			stdout: "debug.log\n",
+		},
+		{
+			name:   "Pass-Verbose",
+			args:   []string{"check-ignore", "-v", "debug.log", "keep.log", "main.go"},
+			code:   exitOK,
+			stdout: ".gitignore:1:*.log\tdebug.log\n.gitignore:2:!keep.log\tkeep.log\n",
+		},
+		{
+			name:   "Pass-NonMatching",
+			args:   []string{"check-ignore", "-v", "-n", "main.go", "build"},
+			code:   exitOK,
+			stdout: "::\tmain.go\n.gitignore:3:build/\tbuild\n",
+		},
+		{
+			name:   "Pass-Stdin",
+			stdin:  "debug.log\nbuild/out.bin\n",
+			args:   []string{"check-ignore", "-stdin"},
+			code:   exitOK,
+			stdout: "debug.log\nbuild/out.bin\n",
+		},
+		{
+			name:   "Pass-StdinNul",
+			stdin:  "debug.log\x00main.go\x00",
+			args:   []string{"check-ignore", "-stdin", "-z", "-v", "-non-matching"},
+			code:   exitOK,
+			stdout: ".gitignore\x001\x00*.log\x00debug.log\x00\x00\x00\x00main.go\x00",
+		},
+		{
+			name:   "Pass-NestedIgnoreFile",
+			args:   []string{"check-ignore", "-v", "a/n.txt", "n.txt"},
+			code:   exitOK,
+			stdout: "a/.gitignore:2:*.txt\ta/n.txt\n",
+		},
+		{
+			name:   "Pass-NestedNegation",
+			args:   []string{"check-ignore", "-v", "a/b.log", "a/c.log"},
+			code:   exitOK,
+			stdout: "a/.gitignore:3:!b.log\ta/b.log\n.gitignore:1:*.log\ta/c.log\n",
+		},
+		{
+			name:   "Pass-QuotedPath",
+			args:   []string{"check-ignore", "caf\u00e9.log", "tab\t\"quoted\".log"},
+			code:   exitOK,
+			stdout: "\"caf\\303\\251.log\"\n\"tab\\t\\\"quoted\\\".log\"\n",
+		},
+		{
+			name:   "Pass-QuotedPathVerbose",
+			args:   []string{"check-ignore", "-v", "caf\u00e9.log"},
+			code:   exitOK,
+			stdout: ".gitignore:1:*.log\t\"caf\\303\\251.log\"\n",
+		},
+		{
+			name:   "Pass-QuotedStdin",
+			stdin:  "\"caf\\303\\251.log\"\n",
+			args:   []string{"check-ignore", "-stdin"},
+			code:   exitOK,
+			stdout: "\"caf\\303\\251.log\"\n",
+		},
+		{
+			name:   "Pass-NulNotQuoted",
+			args:   []string{"check-ignore", "-z", "caf\u00e9.log"},
+			code:   exitOK,
+			stdout: "caf\u00e9.log\x00",
+		},
+		{
+			name: "Fail-NoMatch",
+			args: []string{"check-ignore", "main.go"},
+			code: exitNoMatch,
+		},
+		{
+			name: "Fail-NonMatchingWithoutVerbose",
+			args: []string{"check-ignore", "-n", "main.go"},
+			code: exitError,
+		},
+		{
+			name: "Fail-OutsideDirectory",
+			args: []string{"check-ignore", "../main.go"},
+			code: exitError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, tc.stdin, tc.args...)
+			if code != tc.code {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tc.code, code, stderr)
+			}
+
+			if stdout != tc.stdout {
+				t.Errorf("expected output %q, got %q", tc.stdout, stdout)
+			}
+		})
+	}
+}
+
+func TestRunLint(t *testing.T) {
+	path := writeIgnoreFile(t, "*.log \n./build/\n")
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+	}{
+		{
+			name: "Fail-Findings",
+			args: []string{"lint", "-file", path},
+			code: exitConflicts,
+			stdout: path + ":1: WARNING TRAILING_WHITESPACE: trailing spaces are stripped by git, escape them with a backslash if they are part of the name\n" +
+				path + ":2: ERROR DOT_SLASH_PREFIX: patterns starting with './' or '../' never match, use a leading '/' to anchor to the ignore file's directory\n",
+		},
+		{
+			name:   "Pass-Disabled",
+			args:   []string{"lint", "-file", path, "-disable", "TRAILING_WHITESPACE,DOT_SLASH_PREFIX"},
+			code:   exitOK,
+			stdout: "",
+		},
+		{
+			name:   "Fail-EnableOnly",
+			args:   []string{"lint", "-file", path, "-enable", "DOT_SLASH_PREFIX"},
+			code:   exitConflicts,
+			stdout: path + ":2: ERROR DOT_SLASH_PREFIX: patterns starting with './' or '../' never match, use a leading '/' to anchor to the ignore file's directory\n",
+		},
+		{
+			name:   "Fail-UnknownCheck",
+			args:   []string{"lint", "-file", path, "-disable", "NOT_A_CHECK"},
+			code:   exitError,
+			stdout: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, "", tc.args...)
+			if code != tc.code {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tc.code, code, stderr)
+			}
+
+			if stdout != tc.stdout {
+				t.Errorf("expected output %q, got %q", tc.stdout, stdout)
+			}
+		})
+	}
+}
+
+func TestRunSuggest(t *testing.T) {
+	path := writeIgnoreFile(t, "target/\n*.pdb\n")
+	if err := os.WriteFile(filepath.Join(filepath.Dir(path), "Cargo.toml"), nil, 0o644); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	code, stdout, stderr := runCLI(t, "", "suggest", "-file", path)
+	if code != exitConflicts || stdout != "MISSING: Rule '**/*.rs.bk', Ecosystem: RUST\n" {
+		t.Errorf("expected **/*.rs.bk to be reported with exit code %d, got %d: %q (stderr: %s)", exitConflicts, code, stdout, stderr)
+	}
+
+	code, stdout, stderr = runCLI(t, "", "suggest", "-file", path, "-apply")
+	if code != exitOK || stdout != "ADDED: Rule '**/*.rs.bk', Reason: REQUESTED\n" {
+		t.Errorf("expected **/*.rs.bk to be added, got %d: %q (stderr: %s)", code, stdout, stderr)
+	}
+
+	if expected := "target/\n*.pdb\n\n# Rust\n**/*.rs.bk\n"; readIgnoreFile(t, path) != expected {
+		t.Errorf("expected file %q, got %q", expected, readIgnoreFile(t, path))
+	}
+}
+
+func TestRunDead(t *testing.T) {
+	path := writeIgnoreFile(t, "*.log\n*.pyc\n")
+	if err := os.WriteFile(filepath.Join(filepath.Dir(path), "debug.log"), nil, 0o644); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	code, stdout, stderr := runCLI(t, "", "dead", "-file", path)
+	if code != exitConflicts || stdout != "UNMATCHED_RULE: Rule '*.pyc'\n" {
+		t.Errorf("expected *.pyc to be reported with exit code %d, got %d: %q (stderr: %s)", exitConflicts, code, stdout, stderr)
+	}
+
+	code, stdout, stderr = runCLI(t, "", "dead", "-file", path, "-fix")
+	if code != exitOK || stdout != "REMOVED: Rule '*.pyc', Reason: DEAD_RULE\n" {
+		t.Errorf("expected *.pyc to be removed, got %d: %q (stderr: %s)", code, stdout, stderr)
+	}
+
+	if content := readIgnoreFile(t, path); content != "*.log\n" {
+		t.Errorf("expected file %q, got %q", "*.log\n", content)
+	}
+}
+
+func TestRunTracked(t *testing.T) {
+	root := t.TempDir()
+
+	index, err := os.ReadFile("../../testdata/index-v4")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	files := map[string]string{
+		".git/index": string(index),
+		".gitignore": "*.log\nbuild/\n",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+	}
+
+	code, stdout, stderr := runCLI(t, "", "tracked", "-C", root)
+	if code != exitConflicts {
+		t.Errorf("expected exit code %d, got %d (stderr: %s)", exitConflicts, code, stderr)
+	}
+
+	expected := ".gitignore:1:*.log\ta.log\n.gitignore:2:build/\tbuild/x.c\n"
+	if stdout != expected {
+		t.Errorf("expected output %q, got %q", expected, stdout)
+	}
+}
+
+func TestRunWithoutIndex(t *testing.T) {
+	// A fresh "git init" has no index until something is added
+	root := t.TempDir()
+
+	files := map[string]string{
+		".git/HEAD":  "ref: refs/heads/main\n",
+		".gitignore": "*.log\n",
+		"a.log":      "",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	tests := []struct {
+		name   string
+		args   []string
+		stdout string
+	}{
+		{name: "Pass-CheckIgnore", args: []string{"check-ignore", "-v", "a.log"}, stdout: ".gitignore:1:*.log\ta.log\n"},
+		{name: "Pass-Tracked", args: []string{"tracked", "-C", root}},
+		{name: "Pass-Untracked", args: []string{"untracked", "-C", root}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, "", tc.args...)
+			if code != exitOK {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+			}
+
+			if stdout != tc.stdout {
+				t.Errorf("expected output %q, got %q", tc.stdout, stdout)
+			}
+		})
+	}
+}
+
+func TestRunUntracked(t *testing.T) {
+	root := t.TempDir()
+
+	index, err := os.ReadFile("../../testdata/index-v4")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	files := map[string]string{
+		".git/index":      string(index),
+		".gitignore":      "*.log\nbuild/\n",
+		"main.go":         "",
+		"debug.log":       "",
+		"tmp/a.txt":       "",
+		"tmp/cache/b.txt": "",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+	}
+
+	code, stdout, stderr := runCLI(t, "", "untracked", "-C", root)
+	if code != exitConflicts {
+		t.Errorf("expected exit code %d, got %d (stderr: %s)", exitConflicts, code, stderr)
+	}
+
+	if expected := "tmp/a.txt\ntmp/cache/b.txt\n"; stdout != expected {
+		t.Errorf("expected output %q, got %q", expected, stdout)
+	}
+
+	code, stdout, _ = runCLI(t, "", "untracked", "-C", root, "-suggest")
+	if code != exitConflicts {
+		t.Errorf("expected exit code %d, got %d", exitConflicts, code)
+	}
+
+	if expected := "SUGGESTED: Rule 'tmp/', Covers: 2 paths\n"; stdout != expected {
+		t.Errorf("expected output %q, got %q", expected, stdout)
+	}
+}
+
+func TestRunFormat(t *testing.T) {
+	formatted := writeIgnoreFile(t, "*.log\nbuild/\n")
+	unformatted := writeIgnoreFile(t, "build//\n*.log")
+
+	code, stdout, _ := runCLI(t, "", "fmt", "-check", formatted, unformatted)
+	if code != exitConflicts || stdout != unformatted+"\n" {
+		t.Errorf("expected only the unformatted file to be reported, got %d: %q", code, stdout)
+	}
+
+	if content := readIgnoreFile(t, unformatted); content != "build//\n*.log" {
+		t.Errorf("expected -check to leave the file as it is, got %q", content)
+	}
+
+	if code, _, stderr := runCLI(t, "", "fmt", "-file", unformatted); code != exitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+	}
+
+	if content := readIgnoreFile(t, unformatted); content != "*.log\nbuild/\n" {
+		t.Errorf("expected the file to be formatted, got %q", content)
+	}
+
+	if code, stdout, _ := runCLI(t, "", "fmt", "--check", formatted, unformatted); code != exitOK || stdout != "" {
+		t.Errorf("expected both files to be formatted, got %d: %q", code, stdout)
+	}
+}
+
+func TestRunFormatWarnsAboutDotSlash(t *testing.T) {
+	path := writeIgnoreFile(t, "*.log\n./tmp\n")
+
+	code, _, stderr := runCLI(t, "", "fmt", "-file", path)
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitOK, code, stderr)
+	}
+
+	if expected := path + ":2: DOT_SLASH_PREFIX: pattern never matches and is left unchanged\n"; stderr != expected {
+		t.Errorf("expected warning %q, got %q", expected, stderr)
+	}
+
+	if content := readIgnoreFile(t, path); content != "*.log\n./tmp\n" {
+		t.Errorf("expected the pattern to be left as it is, got %q", content)
+	}
+}
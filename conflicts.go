@@ -1,6 +1,8 @@
 package gignore
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -13,10 +15,56 @@ const (
 	INEFFECTIVE_RULE  ConflictType = "INEFFECTIVE_RULE"  // Different action, but rule subsumes another rule
 )
 
+var unknownConflictTypeError = errors.New("unknown conflict type")
+
+var conflictTypes = []ConflictType{SEMANTIC_CONFLICT, REDUNDANT_RULE, UNREACHABLE_RULE, INEFFECTIVE_RULE}
+
+// Returns the name a conflict type is reported under, in logs, JSON, SARIF and suppression
+// directives. The REDUNDANT_RULE constant's value is misspelled "REDUNANT_RULE", which is kept
+// for compatibility, but every output uses the correct spelling
+func conflictTypeName(conflictType ConflictType) string {
+	if conflictType == REDUNDANT_RULE {
+		return "REDUNDANT_RULE"
+	}
+
+	return string(conflictType)
+}
+
+// Returns the conflict type with a name, accepting both spellings of REDUNDANT_RULE
+func conflictTypeFromName(name string) (ConflictType, error) {
+	for _, conflictType := range conflictTypes {
+		if name == conflictTypeName(conflictType) || name == string(conflictType) {
+			return conflictType, nil
+		}
+	}
+
+	return "", unknownConflictTypeError
+}
+
 type Conflict struct {
 	Left         Ruler
 	Right        Ruler
 	ConflictType ConflictType
+	// Where Left and Right were parsed from, set by IgnoreFile.FindConflicts
+	LeftPosition  Position
+	RightPosition Position
+}
+
+// Log returns a formatted string representation of the Conflict suitable for logging
+// or display purposes.
+//
+// Example output: "UNREACHABLE_RULE: Rules 'build/**' and 'build/'"
+func (c Conflict) Log() string {
+	return fmt.Sprintf("%s: Rules '%s' and '%s'", conflictTypeName(c.ConflictType), c.Left.Render(), c.Right.Render())
+}
+
+// IsConflictError reports whether an error returned while adding a rule was caused by a
+// conflict with an existing rule (a semantic conflict, redundant rule or unreachable rule),
+// rather than by invalid input or a storage failure.
+func IsConflictError(err error) bool {
+	return errors.Is(err, semanticConflictError) ||
+		errors.Is(err, redundantRuleError) ||
+		errors.Is(err, unreachableRuleError)
 }
 
 func checkConflict(left, right Ruler, intervening []Ruler) (Conflict, bool) {
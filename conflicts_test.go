@@ -207,3 +207,34 @@ func TestCheckConflict(t *testing.T) {
 		})
 	}
 }
+
+func TestConflictLog(t *testing.T) {
+	logs, _ := NewExtensionRule("log", INCLUDE)
+	build, _ := NewDirectoryRule("build", RECURSIVE, INCLUDE)
+	buildDir, _ := NewDirectoryRule("build", DIRECTORY, INCLUDE)
+
+	tests := []struct {
+		name     string
+		conflict Conflict
+		expected string
+	}{
+		{
+			name:     "Pass-Redundant",
+			conflict: Conflict{Left: logs, Right: logs, ConflictType: REDUNDANT_RULE},
+			expected: "REDUNDANT_RULE: Rules '*.log' and '*.log'",
+		},
+		{
+			name:     "Pass-Unreachable",
+			conflict: Conflict{Left: build, Right: buildDir, ConflictType: UNREACHABLE_RULE},
+			expected: "UNREACHABLE_RULE: Rules 'build/**' and 'build/'",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if log := tc.conflict.Log(); log != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, log)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package gignore
+
+import (
+	"fmt"
+	"io/fs"
+	"slices"
+)
+
+// DeadRuleType describes why a rule has no effect on a working tree
+type DeadRuleType string
+
+const (
+	UNMATCHED_RULE          DeadRuleType = "UNMATCHED_RULE"          // The rule matches no path
+	NEGATION_WITHOUT_EFFECT DeadRuleType = "NEGATION_WITHOUT_EFFECT" // The negation never re-includes an ignored path
+)
+
+type DeadRule struct {
+	Rule Ruler
+	Type DeadRuleType
+	Line int
+}
+
+// Log returns a formatted string representation of the DeadRule suitable for logging
+// or display purposes.
+//
+// Example output: "UNMATCHED_RULE: Rule '*.pyc'"
+func (d DeadRule) Log() string {
+	return fmt.Sprintf("%s: Rule '%s'", d.Type, d.Rule.Render())
+}
+
+type treePath struct {
+	path  string
+	isDir bool
+}
+
+// Collects every path in the tree, except for the contents of .git
+func walkTree(tree fs.FS) ([]treePath, error) {
+	paths := make([]treePath, 0)
+
+	err := fs.WalkDir(tree, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == "." {
+			return nil
+		}
+
+		if entry.IsDir() && entry.Name() == ".git" {
+			return fs.SkipDir
+		}
+
+		paths = append(paths, treePath{path: path, isDir: entry.IsDir()})
+
+		return nil
+	})
+
+	return paths, err
+}
+
+// FindDeadRules walks a working tree and reports rules that have no effect on it: rules that
+// match no path at all, and negation rules that never change whether a path is ignored (for
+// example because the path is inside an ignored directory, or was not ignored to begin with).
+//
+// Parameters:
+//   - tree: The directory the ignore file applies to, e.g. os.DirFS(".").
+//     The .git directory is skipped.
+//
+// Returns the dead rules in file order and an error if the tree cannot be walked. Findings can
+// be silenced with "# gignore:ignore-next-line <TYPE>" or "# gignore:disable <TYPE>" comments.
+// If the IgnoreFile has a managed block, rules outside of the block are checked as well.
+//
+// Example:
+//
+//	deadRules, err := ignoreFile.FindDeadRules(os.DirFS("."))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, deadRule := range deadRules {
+//	    fmt.Println(deadRule.Log())
+//	}
+func (f IgnoreFile) FindDeadRules(tree fs.FS) ([]DeadRule, error) {
+	paths, err := walkTree(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.findDeadRules(paths), nil
+}
+
+func (f IgnoreFile) findDeadRules(paths []treePath) []DeadRule {
+	rules := f.allRules()
+	matched := make([]bool, len(rules))
+	flips := make([]bool, len(rules))
+
+	for _, entry := range paths {
+		for idx, rule := range rules {
+			if !matched[idx] && matchesRule(rule, entry.path, entry.isDir) {
+				matched[idx] = true
+			}
+		}
+
+		idx := decidingRule(rules, entry.path, entry.isDir)
+		if idx == -1 || rules[idx].Action() != EXCLUDE || flips[idx] {
+			continue
+		}
+
+		// The negation only matters if the path would be ignored without it
+		without := slices.Delete(slices.Clone(rules), idx, idx+1)
+		if other := decidingRule(without, entry.path, entry.isDir); other != -1 && without[other].Action() == INCLUDE {
+			flips[idx] = true
+		}
+	}
+
+	suppressed := f.fileSuppressions()
+	lines := f.allLines()
+	deadRules := make([]DeadRule, 0)
+
+	for idx, rule := range rules {
+		var deadType DeadRuleType
+
+		switch {
+		case !matched[idx]:
+			deadType = UNMATCHED_RULE
+		case rule.Action() == EXCLUDE && !flips[idx]:
+			deadType = NEGATION_WITHOUT_EFFECT
+		default:
+			continue
+		}
+
+		if suppressed.covers(string(deadType)) || f.ruleSuppressions(rule).covers(string(deadType)) {
+			continue
+		}
+
+		deadRules = append(deadRules, DeadRule{Rule: rule, Type: deadType, Line: lines[idx]})
+	}
+
+	return deadRules
+}
+
+// RemoveDeadRules removes the rules reported by FindDeadRules without changing which paths of
+// the tree are ignored. Unmatched rules are removed at once. Negations are removed one at a
+// time, re-checking the rest after each removal, because two negations that cover the same
+// path each look redundant while the other one is present.
+//
+// Parameters:
+//   - tree: The directory the ignore file applies to, e.g. os.DirFS(".").
+//
+// Returns a slice of Result with a REMOVED result and DEAD_RULE reason for each removed rule,
+// and an error if the tree cannot be walked. Dead rules outside of a managed block are not
+// removed and are reported with a REVIEW_RECOMMENDED result instead.
+//
+// Example:
+//
+//	results, err := ignoreFile.RemoveDeadRules(os.DirFS("."))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (f *IgnoreFile) RemoveDeadRules(tree fs.FS) ([]Result, error) {
+	paths, err := walkTree(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0)
+	reviewed := make(map[string]bool)
+
+	for {
+		removedNegation := false
+
+		for _, deadRule := range f.findDeadRules(paths) {
+			if !f.isManaged(deadRule.Rule) {
+				if !reviewed[ruleKey(deadRule.Rule)] {
+					reviewed[ruleKey(deadRule.Rule)] = true
+					results = append(results, Result{
+						Rule:     deadRule.Rule,
+						Result:   REVIEW_RECOMMENDED,
+						Reason:   DEAD_RULE,
+						Position: Position{Source: f.source, Line: deadRule.Line},
+					})
+				}
+				continue
+			}
+
+			if deadRule.Type == NEGATION_WITHOUT_EFFECT {
+				if removedNegation {
+					continue // re-check once the previous negation is gone
+				}
+				removedNegation = true
+			}
+
+			result, err := f.deleteMatchingRule(deadRule.Rule, DEAD_RULE)
+			if err != nil {
+				return results, err
+			}
+
+			results = append(results, result)
+		}
+
+		if !removedNegation {
+			return results, nil
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package gignore
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func testTree() fstest.MapFS {
+	return fstest.MapFS{
+		"main.go":          {},
+		"debug.log":        {},
+		"keep.log":         {},
+		"build/output.bin": {},
+		"build/keep.txt":   {},
+		".git/config":      {},
+	}
+}
+
+func TestFindDeadRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []DeadRule
+	}{
+		{
+			name:     "Pass-NoDeadRules",
+			content:  "*.log\n!keep.log\nbuild/",
+			expected: []DeadRule{},
+		},
+		{
+			name:    "Pass-Unmatched",
+			content: "*.log\n*.pyc\nnode_modules/\nconfig",
+			expected: []DeadRule{
+				{Rule: ExtensionRule{ext: "pyc", act: INCLUDE}, Type: UNMATCHED_RULE, Line: 2},
+				{Rule: DirectoryRule{name: "node_modules", mode: DIRECTORY, act: INCLUDE}, Type: UNMATCHED_RULE, Line: 3},
+				{Rule: FileRule{path: "config", act: INCLUDE}, Type: UNMATCHED_RULE, Line: 4}, // .git is skipped
+			},
+		},
+		{
+			name:    "Pass-NegationInsideIgnoredDirectory",
+			content: "build/\n!build/keep.txt",
+			expected: []DeadRule{
+				{Rule: FileRule{path: "build/keep.txt", act: EXCLUDE}, Type: NEGATION_WITHOUT_EFFECT, Line: 2},
+			},
+		},
+		{
+			name:    "Pass-NegationOfUnignoredPath",
+			content: "*.log\n!main.go",
+			expected: []DeadRule{
+				{Rule: FileRule{path: "main.go", act: EXCLUDE}, Type: NEGATION_WITHOUT_EFFECT, Line: 2},
+			},
+		},
+		{
+			name:     "Pass-Suppressed",
+			content:  "*.log\n# gignore:ignore-next-line UNMATCHED_RULE\n*.pyc",
+			expected: []DeadRule{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore := NewIgnoreFile()
+			Parse(tc.content, &ignore)
+
+			deadRules, err := ignore.FindDeadRules(testTree())
+			checkErrors("", err, t)
+
+			if len(deadRules) != len(tc.expected) {
+				t.Fatalf("expected %d dead rules, got %d: %v", len(tc.expected), len(deadRules), deadRules)
+			}
+
+			for idx, deadRule := range deadRules {
+				expected := tc.expected[idx]
+				if !rulesEqual(deadRule.Rule, expected.Rule) || deadRule.Type != expected.Type || deadRule.Line != expected.Line {
+					t.Errorf("expected %s (line %d), got %s (line %d)", expected.Log(), expected.Line, deadRule.Log(), deadRule.Line)
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveDeadRules(t *testing.T) {
+	// Both negations cover keep.log, only one of them may go
+	content := "*.log\n!keep*\n!*keep.log\n*.pyc"
+
+	ignore := NewIgnoreFile()
+	Parse(content, &ignore)
+
+	results, err := ignore.RemoveDeadRules(testTree())
+	checkErrors("", err, t)
+
+	expected := []string{
+		"REMOVED: Rule '!keep*', Reason: DEAD_RULE",
+		"REMOVED: Rule '*.pyc', Reason: DEAD_RULE",
+	}
+
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d: %v", len(expected), len(results), results)
+	}
+
+	for idx, result := range results {
+		if result.Log() != expected[idx] {
+			t.Errorf("expected %q, got %q", expected[idx], result.Log())
+		}
+	}
+
+	if !ignore.IsIgnored("debug.log") || ignore.IsIgnored("keep.log") {
+		t.Errorf("expected removing dead rules to preserve matching behavior")
+	}
+}
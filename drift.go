@@ -0,0 +1,343 @@
+package gignore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Files created from templates record each template in a header comment:
+//
+//	# gignore:template go sha256:9f2c…e1 rules:1a2b3c4d,5e6f7a8b
+//
+// The hash covers the template's rules, so changes to comments upstream are not drift, and the
+// fingerprints identify the rules the template had, which serves as the base of a three-way merge.
+
+// MARK: Records
+const TEMPLATE_DIRECTIVE = "gignore:template"
+
+const (
+	templateHashPrefix  = "sha256:"
+	templateRulesPrefix = "rules:"
+	fingerprintLength   = 8 // hex characters
+)
+
+// TemplateRecord describes a template an ignore file was generated from.
+type TemplateRecord struct {
+	// Name is the name of the template, as known to its TemplateSource
+	Name string
+	// Hash is the SHA-256 of the template's rules, in hex
+	Hash string
+	// Fingerprints identify the template's rules, see ruleFingerprint
+	Fingerprints []string
+}
+
+// Template names are stored as a single field of the record comment, so they can't contain whitespace
+func validateTemplateName(name string) error {
+	if strings.ContainsFunc(name, unicode.IsSpace) {
+		return invalidTemplateNameError
+	}
+
+	return nil
+}
+
+func newTemplateRecord(name string, template IgnoreFile) TemplateRecord {
+	rules := template.Rules()
+
+	fingerprints := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		fingerprints = append(fingerprints, ruleFingerprint(rule))
+	}
+
+	return TemplateRecord{Name: name, Hash: templateHash(rules), Fingerprints: fingerprints}
+}
+
+func templateHash(rules []Ruler) string {
+	rendered := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		rendered = append(rendered, rule.Render())
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(rendered, "\n")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Returns a short hash identifying a rule by its pattern and action
+func ruleFingerprint(rule Ruler) string {
+	sum := sha256.Sum256([]byte(rule.Render()))
+
+	return hex.EncodeToString(sum[:])[:fingerprintLength]
+}
+
+func (r TemplateRecord) comment() string {
+	return fmt.Sprintf("# %s %s %s%s %s%s",
+		TEMPLATE_DIRECTIVE,
+		r.Name,
+		templateHashPrefix, r.Hash,
+		templateRulesPrefix, strings.Join(r.Fingerprints, ","),
+	)
+}
+
+func (r TemplateRecord) hasFingerprint(rule Ruler) bool {
+	return slices.Contains(r.Fingerprints, ruleFingerprint(rule))
+}
+
+// Parses a "# gignore:template" comment
+func parseTemplateRecord(line string) (TemplateRecord, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "#") {
+		return TemplateRecord{}, false
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(line, "#"))
+	if len(fields) < 3 || fields[0] != TEMPLATE_DIRECTIVE || !strings.HasPrefix(fields[2], templateHashPrefix) {
+		return TemplateRecord{}, false
+	}
+
+	record := TemplateRecord{Name: fields[1], Hash: strings.TrimPrefix(fields[2], templateHashPrefix)}
+
+	if len(fields) > 3 && strings.HasPrefix(fields[3], templateRulesPrefix) {
+		if list := strings.TrimPrefix(fields[3], templateRulesPrefix); list != "" {
+			record.Fingerprints = strings.Split(list, ",")
+		}
+	}
+
+	return record, true
+}
+
+// Collects the template records in lines and blanks them out so they are not parsed as comments
+func extractTemplateRecords(lines []string) []TemplateRecord {
+	var records []TemplateRecord
+
+	for idx, line := range lines {
+		if record, ok := parseTemplateRecord(line); ok {
+			records = append(records, record)
+			lines[idx] = ""
+		}
+	}
+
+	return records
+}
+
+// Templates returns the templates the IgnoreFile was generated from, in the order they were applied.
+func (f IgnoreFile) Templates() []TemplateRecord {
+	return slices.Clone(f.templates)
+}
+
+// Adds a template record, replacing an earlier record of the same template
+func (f *IgnoreFile) recordTemplate(record TemplateRecord) {
+	for idx, existing := range f.templates {
+		if existing.Name == record.Name {
+			f.templates[idx] = record
+			return
+		}
+	}
+
+	f.templates = append(f.templates, record)
+}
+
+// MARK: Drift
+
+// DriftType describes how a rule differs between a file and the template it was generated from
+type DriftType string
+
+const (
+	ADDED_UPSTREAM   DriftType = "ADDED_UPSTREAM"   // The template gained the rule
+	REMOVED_UPSTREAM DriftType = "REMOVED_UPSTREAM" // The template dropped the rule, the file still has it
+	ADDED_LOCALLY    DriftType = "ADDED_LOCALLY"    // The file added the rule to the template's section
+	REMOVED_LOCALLY  DriftType = "REMOVED_LOCALLY"  // The file dropped a rule the template still has
+)
+
+type Drift struct {
+	Template string
+	Rule     Ruler
+	Type     DriftType
+}
+
+// Log returns a formatted string representation of the Drift suitable for logging
+// or display purposes.
+//
+// Example output: "ADDED_UPSTREAM: Rule '*.prof', Template: go"
+func (d Drift) Log() string {
+	return fmt.Sprintf("%s: Rule '%s', Template: %s", d.Type, d.Rule.Render(), d.Template)
+}
+
+// FindTemplateDrift compares the IgnoreFile with the current version of each template it was
+// generated from. Rules the template gained or dropped since the file was generated are
+// reported as ADDED_UPSTREAM and REMOVED_UPSTREAM. Local edits are reported as ADDED_LOCALLY
+// (rules in the template's section that the template never had) and REMOVED_LOCALLY (template
+// rules the file no longer has); a rule edited locally shows up as both. Rules are compared in
+// the canonical form of Format, so formatting the file is not drift.
+//
+// Parameters:
+//   - source: The TemplateSource holding the current templates, e.g. EmbeddedTemplates().
+//
+// Returns the drift for each recorded template in record order, and an error if a template
+// cannot be loaded. Files without template records have no drift.
+//
+// Example:
+//
+//	drift, err := ignoreFile.FindTemplateDrift(EmbeddedTemplates())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, d := range drift {
+//	    fmt.Println(d.Log())
+//	}
+func (f IgnoreFile) FindTemplateDrift(source TemplateSource) ([]Drift, error) {
+	drift := make([]Drift, 0)
+
+	for _, record := range f.templates {
+		upstream, err := source.Template(record.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		drift = append(drift, f.templateDrift(record, upstream)...)
+	}
+
+	return drift, nil
+}
+
+func (f IgnoreFile) templateDrift(record TemplateRecord, upstream IgnoreFile) []Drift {
+	drift := make([]Drift, 0)
+	local := f.allRules()
+	upstreamRules := upstream.Rules()
+
+	sameRule := func(left, right Ruler) bool {
+		return rulesEqual(canonicalRule(left), canonicalRule(right))
+	}
+
+	hasRule := func(rules []Ruler, rule Ruler) bool {
+		return slices.ContainsFunc(rules, func(other Ruler) bool { return sameRule(other, rule) })
+	}
+
+	// Fingerprints are taken from the template's own spelling of a rule
+	recorded := func(rule Ruler) bool {
+		return record.hasFingerprint(rule) || slices.ContainsFunc(upstreamRules, func(other Ruler) bool {
+			return sameRule(other, rule) && record.hasFingerprint(other)
+		})
+	}
+
+	for _, rule := range upstreamRules {
+		switch {
+		case !record.hasFingerprint(rule):
+			drift = append(drift, Drift{Template: record.Name, Rule: rule, Type: ADDED_UPSTREAM})
+		case !hasRule(local, rule):
+			drift = append(drift, Drift{Template: record.Name, Rule: rule, Type: REMOVED_LOCALLY})
+		}
+	}
+
+	label := templateLabel(record.Name)
+	for _, rule := range local {
+		if !recorded(rule) {
+			if f.SectionOf(rule) == label && !hasRule(upstreamRules, rule) {
+				drift = append(drift, Drift{Template: record.Name, Rule: rule, Type: ADDED_LOCALLY})
+			}
+			continue
+		}
+
+		if !hasRule(upstreamRules, rule) {
+			drift = append(drift, Drift{Template: record.Name, Rule: rule, Type: REMOVED_UPSTREAM})
+		}
+	}
+
+	return drift
+}
+
+// MARK: Upgrade
+
+// Upgrade brings the IgnoreFile up to date with the current version of each template it was
+// generated from, using a three-way merge between the rules the template had when the file was
+// generated, the rules the file has now, and the rules the template has now:
+//   - Rules added upstream are added to the template's section
+//   - Rules removed upstream are removed, unless they are outside of a managed block
+//   - Local additions and removals are kept
+//
+// Template records are updated afterwards, so the same upstream change is never applied twice.
+//
+// Parameters:
+//   - source: The TemplateSource holding the current templates, e.g. EmbeddedTemplates().
+//
+// Returns a slice of Result describing every change, with reason UPGRADED, and an error if a
+// template cannot be loaded or conflict resolution fails. Upstream rules that conflict with
+// local edits are not applied: a rule the file negates is reported as REVIEW_RECOMMENDED with
+// reason FIX_UNKNOWN, and a rule already covered by a broader local rule as UNCHANGED. The
+// IgnoreFile is only modified if the upgrade succeeds.
+//
+// Example:
+//
+//	results, err := ignoreFile.Upgrade(EmbeddedTemplates())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, result := range results {
+//	    fmt.Println(result.Log())
+//	}
+func (f *IgnoreFile) Upgrade(source TemplateSource) ([]Result, error) {
+	working := f.clone()
+	results := make([]Result, 0)
+
+	for _, record := range f.templates {
+		upstream, err := source.Template(record.Name)
+		if err != nil {
+			return make([]Result, 0), err
+		}
+
+		if templateHash(upstream.Rules()) == record.Hash {
+			continue
+		}
+
+		for _, drift := range working.templateDrift(record, upstream) {
+			var changes []Result
+
+			switch drift.Type {
+			case ADDED_UPSTREAM:
+				changes, err = working.addSuggestedRule(templateLabel(record.Name), drift.Rule)
+			case REMOVED_UPSTREAM:
+				changes, err = working.removeUpstreamRule(drift.Rule)
+			}
+			if err != nil {
+				return make([]Result, 0), err
+			}
+
+			if drift.Type == ADDED_UPSTREAM && len(changes) > 0 && changes[0].Result == ADDED {
+				changes[0].Reason = UPGRADED
+			}
+
+			results = append(results, changes...)
+		}
+
+		working.recordTemplate(newTemplateRecord(record.Name, upstream))
+	}
+
+	fixes, err := working.FixConflicts(20)
+	if err != nil {
+		return make([]Result, 0), err
+	}
+
+	results = append(results, fixes...)
+	*f = working
+
+	return results, nil
+}
+
+func (f *IgnoreFile) removeUpstreamRule(rule Ruler) ([]Result, error) {
+	if f.findRuleIndex(rule) == -1 {
+		// Rules outside of a managed block are never modified
+		return []Result{{Rule: rule, Result: REVIEW_RECOMMENDED, Reason: FIX_UNKNOWN, Position: f.PositionOf(rule)}}, nil
+	}
+
+	removal, err := f.deleteMatchingRule(rule, UPGRADED)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Result{removal}, nil
+}
@@ -0,0 +1,209 @@
+package gignore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Returns the template records a file generated from the templates starts with, computed
+// from the rules of each template
+func templateHeader(t *testing.T, source TemplateSource, names ...string) string {
+	t.Helper()
+
+	var header strings.Builder
+	for _, name := range names {
+		template, err := source.Template(name)
+		checkErrors("", err, t)
+
+		rendered := make([]string, 0)
+		fingerprints := make([]string, 0)
+		for _, rule := range template.Rules() {
+			sum := sha256.Sum256([]byte(rule.Render()))
+			rendered = append(rendered, rule.Render())
+			fingerprints = append(fingerprints, hex.EncodeToString(sum[:])[:8])
+		}
+
+		sum := sha256.Sum256([]byte(strings.Join(rendered, "\n")))
+		fmt.Fprintf(&header, "# gignore:template %s sha256:%x rules:%s\n", name, sum, strings.Join(fingerprints, ","))
+	}
+
+	return header.String()
+}
+
+// Generates a file from version 1 of a template, edits it locally and reparses it
+func driftedIgnoreFile(t *testing.T) IgnoreFile {
+	t.Helper()
+
+	v1 := NewMapTemplateSource(map[string]string{
+		"company": "*.secret\n.cache/\ntmp/\n*.bak\n",
+	})
+
+	composed, _, err := ComposeTemplatesFrom(v1, "company")
+	checkErrors("", err, t)
+
+	// Drop *.bak and edit tmp/ into temp/
+	_, err = composed.EnsureAbsent(ExtensionRule{ext: "bak", act: INCLUDE})
+	checkErrors("", err, t)
+	_, err = composed.EnsureAbsent(DirectoryRule{name: "tmp", mode: DIRECTORY, act: INCLUDE})
+	checkErrors("", err, t)
+	_, err = composed.AddToSection("company", DirectoryRule{name: "temp", mode: DIRECTORY, act: INCLUDE})
+	checkErrors("", err, t)
+
+	reparsed := NewIgnoreFile()
+	checkErrors("", Parse(Render(&composed, RenderOptions{}), &reparsed), t)
+
+	return reparsed
+}
+
+// Version 2 drops .cache/ and adds *.swp
+func upstreamTemplates() TemplateSource {
+	return NewMapTemplateSource(map[string]string{
+		"company": "*.secret\ntmp/\n*.bak\n*.swp\n",
+	})
+}
+
+func TestTemplateRecordRoundTrip(t *testing.T) {
+	content := "# gignore:template Global/macOS sha256:abc123 rules:1a2b3c4d,5e6f7a8b\n\n# macOS\n.DS_Store"
+
+	ignore := NewIgnoreFile()
+	checkErrors("", Parse(content, &ignore), t)
+
+	expected := []TemplateRecord{{Name: "Global/macOS", Hash: "abc123", Fingerprints: []string{"1a2b3c4d", "5e6f7a8b"}}}
+	if records := ignore.Templates(); !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected %v, got %v", expected, records)
+	}
+
+	if rendered := Render(&ignore, RenderOptions{}); rendered != content {
+		t.Errorf("expected %q, got %q", content, rendered)
+	}
+}
+
+func TestFindTemplateDrift(t *testing.T) {
+	ignore := driftedIgnoreFile(t)
+
+	drift, err := ignore.FindTemplateDrift(upstreamTemplates())
+	checkErrors("", err, t)
+
+	expected := []string{
+		"REMOVED_LOCALLY: Rule 'tmp/', Template: company",
+		"REMOVED_LOCALLY: Rule '*.bak', Template: company",
+		"ADDED_UPSTREAM: Rule '*.swp', Template: company",
+		"REMOVED_UPSTREAM: Rule '.cache/', Template: company",
+		"ADDED_LOCALLY: Rule 'temp/', Template: company",
+	}
+
+	logs := make([]string, 0, len(drift))
+	for _, d := range drift {
+		logs = append(logs, d.Log())
+	}
+
+	if !reflect.DeepEqual(logs, expected) {
+		t.Errorf("expected %v, got %v", expected, logs)
+	}
+
+	_, err = ignore.FindTemplateDrift(NewMapTemplateSource(map[string]string{}))
+	checkErrors(unknownTemplateError.Error(), err, t)
+}
+
+func TestUpgrade(t *testing.T) {
+	ignore := driftedIgnoreFile(t)
+
+	results, err := ignore.Upgrade(upstreamTemplates())
+	checkErrors("", err, t)
+
+	expected := []string{
+		"ADDED: Rule '*.swp', Reason: UPGRADED",
+		"REMOVED: Rule '.cache/', Reason: UPGRADED",
+	}
+
+	logs := make([]string, 0, len(results))
+	for _, result := range results {
+		logs = append(logs, result.Log())
+	}
+
+	if !reflect.DeepEqual(logs, expected) {
+		t.Errorf("expected %v, got %v", expected, logs)
+	}
+
+	// Local edits are kept: *.bak and tmp/ stay removed, temp/ stays
+	rendered := Render(&ignore, RenderOptions{})
+	if expected := "\n\n# company\n*.secret\ntemp/\n*.swp"; !strings.HasSuffix(rendered, expected) {
+		t.Errorf("expected file to end with %q, got %q", expected, rendered)
+	}
+
+	// The record now describes the new version, so upgrading again changes nothing
+	results, err = ignore.Upgrade(upstreamTemplates())
+	checkErrors("", err, t)
+
+	if len(results) != 0 {
+		t.Errorf("expected a second upgrade to change nothing, got %v", results)
+	}
+}
+
+func TestUpgradeConflicts(t *testing.T) {
+	v1 := NewMapTemplateSource(map[string]string{"company": "*.secret\n"})
+	v2 := NewMapTemplateSource(map[string]string{"company": "*.secret\ndist/\ndebug.log\n"})
+
+	ignore, _, err := ComposeTemplatesFrom(v1, "company")
+	checkErrors("", err, t)
+
+	// The file deliberately keeps dist/ and already ignores every log
+	_, err = ignore.AddDirectory("dist", DIRECTORY, EXCLUDE)
+	checkErrors("", err, t)
+	_, err = ignore.AddExtension("log", INCLUDE)
+	checkErrors("", err, t)
+
+	results, err := ignore.Upgrade(v2)
+	checkErrors("", err, t)
+
+	expected := []string{
+		"REVIEW_RECOMMENDED: Rule 'dist/', Reason: FIX_UNKNOWN",
+		"UNCHANGED: Rule 'debug.log', Reason: REQUESTED",
+	}
+
+	logs := make([]string, 0, len(results))
+	for _, result := range results {
+		logs = append(logs, result.Log())
+	}
+
+	if !reflect.DeepEqual(logs, expected) {
+		t.Errorf("expected %v, got %v", expected, logs)
+	}
+}
+
+func TestTemplateNameWithWhitespace(t *testing.T) {
+	source := NewMapTemplateSource(map[string]string{"company base": "*.secret\n"})
+
+	_, _, err := ComposeTemplatesFrom(source, "company base")
+	checkErrors(invalidTemplateNameError.Error(), err, t)
+
+	ignore := NewIgnoreFile()
+	_, err = ignore.AddTemplate(source, "company base")
+	checkErrors(invalidTemplateNameError.Error(), err, t)
+
+	if rendered := Render(&ignore, RenderOptions{}); rendered != "" {
+		t.Errorf("expected the file to be left alone, got %q", rendered)
+	}
+}
+
+func TestFindTemplateDriftFormatted(t *testing.T) {
+	source := NewMapTemplateSource(map[string]string{"rust": "target/\n**/*.rs.bk\n"})
+
+	ignore, _, err := ComposeTemplatesFrom(source, "rust")
+	checkErrors("", err, t)
+
+	if rendered := Render(&ignore, RenderOptions{}); !strings.HasSuffix(rendered, "# Rust\n*.rs.bk\ntarget/") {
+		t.Fatalf("expected the composed file in canonical form, got %q", rendered)
+	}
+
+	drift, err := ignore.FindTemplateDrift(source)
+	checkErrors("", err, t)
+
+	if len(drift) != 0 {
+		t.Errorf("expected formatting not to count as drift, got %v", drift)
+	}
+}
@@ -0,0 +1,237 @@
+package gignore
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// Ecosystem identifies a language or tool whose projects share a standard set of ignore rules
+type Ecosystem string
+
+const (
+	GO        Ecosystem = "GO"
+	NODE      Ecosystem = "NODE"
+	PYTHON    Ecosystem = "PYTHON"
+	RUST      Ecosystem = "RUST"
+	JAVA      Ecosystem = "JAVA"
+	DOTNET    Ecosystem = "DOTNET"
+	TERRAFORM Ecosystem = "TERRAFORM"
+)
+
+type ecosystemDefinition struct {
+	ecosystem Ecosystem
+	template  string   // the embedded template holding the standard rules, also used for the section name
+	markers   []string // file name patterns that identify a project, see path.Match
+}
+
+var ecosystemDefinitions = []ecosystemDefinition{
+	{ecosystem: GO, template: "go", markers: []string{"go.mod", "go.work"}},
+	{ecosystem: NODE, template: "node", markers: []string{"package.json"}},
+	{ecosystem: PYTHON, template: "python", markers: []string{"pyproject.toml", "setup.py", "setup.cfg", "requirements.txt", "Pipfile"}},
+	{ecosystem: RUST, template: "rust", markers: []string{"Cargo.toml"}},
+	{ecosystem: JAVA, template: "java", markers: []string{"pom.xml", "build.gradle", "build.gradle.kts"}},
+	{ecosystem: DOTNET, template: "dotnet", markers: []string{"*.csproj", "*.fsproj", "*.vbproj", "*.sln"}},
+	{ecosystem: TERRAFORM, template: "terraform", markers: []string{"*.tf"}},
+}
+
+func (e Ecosystem) definition() (ecosystemDefinition, bool) {
+	for _, def := range ecosystemDefinitions {
+		if def.ecosystem == e {
+			return def, true
+		}
+	}
+
+	return ecosystemDefinition{}, false
+}
+
+// Rules returns the standard ignore rules for projects of the Ecosystem, the rules of the
+// embedded template of the same name (see LoadTemplate). Returns nil for an unknown Ecosystem.
+func (e Ecosystem) Rules() []Ruler {
+	def, ok := e.definition()
+	if !ok {
+		return nil
+	}
+
+	template, err := EmbeddedTemplates().Template(def.template)
+	if err != nil {
+		return nil
+	}
+
+	return template.Rules()
+}
+
+// Detection is an Ecosystem found in a directory, with the marker files that identified it
+type Detection struct {
+	Ecosystem Ecosystem
+	Markers   []string
+}
+
+// DetectEcosystems inspects the top level of a directory for the marker files of known
+// ecosystems, such as go.mod, package.json, pyproject.toml, Cargo.toml, pom.xml, *.csproj
+// or *.tf files.
+//
+// Parameters:
+//   - tree: The directory to inspect, e.g. os.DirFS(".").
+//
+// Returns a Detection for each ecosystem found, in a stable order, and an error if the
+// directory cannot be read.
+//
+// Example:
+//
+//	detections, err := DetectEcosystems(os.DirFS("."))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, detection := range detections {
+//	    fmt.Println(detection.Ecosystem, detection.Markers) // GO [go.mod]
+//	}
+func DetectEcosystems(tree fs.FS) ([]Detection, error) {
+	entries, err := fs.ReadDir(tree, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	detections := make([]Detection, 0)
+
+	for _, def := range ecosystemDefinitions {
+		var markers []string
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			for _, marker := range def.markers {
+				if matched, _ := path.Match(marker, entry.Name()); matched {
+					markers = append(markers, entry.Name())
+					break
+				}
+			}
+		}
+
+		if len(markers) > 0 {
+			detections = append(detections, Detection{Ecosystem: def.ecosystem, Markers: markers})
+		}
+	}
+
+	return detections, nil
+}
+
+// RuleSuggestion is a standard rule of an Ecosystem that an ignore file does not contain
+type RuleSuggestion struct {
+	Ecosystem Ecosystem
+	Rule      Ruler
+}
+
+// Log returns a formatted string representation of the RuleSuggestion suitable for logging
+// or display purposes.
+//
+// Example output: "MISSING: Rule 'node_modules/', Ecosystem: NODE"
+func (s RuleSuggestion) Log() string {
+	return fmt.Sprintf("MISSING: Rule '%s', Ecosystem: %s", s.Rule.Render(), s.Ecosystem)
+}
+
+// MissingRules reports the standard rules of the given ecosystems that the IgnoreFile does not
+// contain. A rule counts as present if a rule with the same pattern and action exists; rules
+// shared by several ecosystems are reported once, for the first ecosystem.
+//
+// Parameters:
+//   - ecosystems: The ecosystems to check, e.g. from DetectEcosystems.
+//
+// Returns a RuleSuggestion for each missing rule, in ecosystem order.
+// If the IgnoreFile has a managed block, rules outside of the block count as present.
+//
+// Example:
+//
+//	for _, suggestion := range ignoreFile.MissingRules(NODE, TERRAFORM) {
+//	    fmt.Println(suggestion.Log())
+//	}
+func (f IgnoreFile) MissingRules(ecosystems ...Ecosystem) []RuleSuggestion {
+	present := make(map[string]bool)
+	for _, rule := range f.allRules() {
+		present[ruleKey(rule)] = true
+	}
+
+	suggestions := make([]RuleSuggestion, 0)
+
+	for _, ecosystem := range ecosystems {
+		for _, rule := range ecosystem.Rules() {
+			if present[ruleKey(rule)] {
+				continue
+			}
+
+			present[ruleKey(rule)] = true
+			suggestions = append(suggestions, RuleSuggestion{Ecosystem: ecosystem, Rule: rule})
+		}
+	}
+
+	return suggestions
+}
+
+// SuggestEcosystemRules detects the ecosystems of a directory and reports their standard rules
+// that the IgnoreFile does not contain. See DetectEcosystems and MissingRules.
+//
+// Parameters:
+//   - tree: The directory the ignore file applies to, e.g. os.DirFS(".").
+//
+// Returns a RuleSuggestion for each missing rule and an error if the directory cannot be read.
+func (f IgnoreFile) SuggestEcosystemRules(tree fs.FS) ([]RuleSuggestion, error) {
+	detections, err := DetectEcosystems(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	ecosystems := make([]Ecosystem, 0, len(detections))
+	for _, detection := range detections {
+		ecosystems = append(ecosystems, detection.Ecosystem)
+	}
+
+	return f.MissingRules(ecosystems...), nil
+}
+
+// ApplySuggestions adds suggested rules with automatic conflict resolution. Each rule is added
+// to a section labelled after its ecosystem (for example "Node"), unless conflict resolution
+// needs to place it elsewhere. Rules that are already present are skipped.
+//
+// Suggestions never override the file: a suggestion made redundant or unreachable by an existing
+// rule is reported as UNCHANGED, and one the file negates (for example "!dist/" when "dist/" is
+// suggested) is reported as REVIEW_RECOMMENDED with reason FIX_UNKNOWN. Neither is added.
+//
+// Parameters:
+//   - suggestions: The rules to add, e.g. from SuggestEcosystemRules or MissingRules.
+//
+// Returns a slice of Result describing every change and an error if conflict resolution fails.
+// The IgnoreFile is only modified if every suggestion is applied.
+//
+// Example:
+//
+//	suggestions, err := ignoreFile.SuggestEcosystemRules(os.DirFS("."))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	results, err := ignoreFile.ApplySuggestions(suggestions)
+func (f *IgnoreFile) ApplySuggestions(suggestions []RuleSuggestion) ([]Result, error) {
+	working := f.clone()
+	results := make([]Result, 0)
+
+	for _, suggestion := range suggestions {
+		section := string(suggestion.Ecosystem)
+		if def, ok := suggestion.Ecosystem.definition(); ok {
+			section = templateLabel(def.template)
+		}
+
+		added, err := working.addSuggestedRule(section, suggestion.Rule)
+		if err != nil {
+			return make([]Result, 0), err
+		}
+
+		results = append(results, added...)
+	}
+
+	*f = working
+
+	return results, nil
+}
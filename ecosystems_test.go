@@ -0,0 +1,152 @@
+package gignore
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDetectEcosystems(t *testing.T) {
+	tree := fstest.MapFS{
+		"go.mod":            {},
+		"package.json":      {},
+		"main.tf":           {},
+		"variables.tf":      {},
+		"App.csproj":        {},
+		"Cargo.toml/readme": {}, // a directory named like a marker is not a marker
+		"README.md":         {},
+	}
+
+	detections, err := DetectEcosystems(tree)
+	checkErrors("", err, t)
+
+	expected := []Detection{
+		{Ecosystem: GO, Markers: []string{"go.mod"}},
+		{Ecosystem: NODE, Markers: []string{"package.json"}},
+		{Ecosystem: DOTNET, Markers: []string{"App.csproj"}},
+		{Ecosystem: TERRAFORM, Markers: []string{"main.tf", "variables.tf"}},
+	}
+
+	if !reflect.DeepEqual(detections, expected) {
+		t.Errorf("expected %v, got %v", expected, detections)
+	}
+}
+
+func TestEcosystemRules(t *testing.T) {
+	for _, def := range ecosystemDefinitions {
+		t.Run(string(def.ecosystem), func(t *testing.T) {
+			template, err := LoadTemplate(def.template)
+			checkErrors("", err, t)
+
+			if rules := def.ecosystem.Rules(); !reflect.DeepEqual(rules, template.Rules()) {
+				t.Errorf("expected the rules of template %s, got %v", def.template, rules)
+			}
+		})
+	}
+
+	if rules := Ecosystem("COBOL").Rules(); rules != nil {
+		t.Errorf("expected no rules for an unknown ecosystem, got %v", rules)
+	}
+}
+
+func TestMissingRules(t *testing.T) {
+	ignore := NewIgnoreFile()
+	checkErrors("", Parse("*.class\n*.log\n*.war\n*.ear\n*.nar\n.mvn/timing.properties\nhs_err_pid*\nreplay_pid*\n", &ignore), t)
+
+	// target/ is a standard rule of both ecosystems, it is reported once
+	expected := []string{
+		"MISSING: Rule 'target/', Ecosystem: RUST",
+		"MISSING: Rule '**/*.rs.bk', Ecosystem: RUST",
+		"MISSING: Rule '*.pdb', Ecosystem: RUST",
+		"MISSING: Rule '.gradle/', Ecosystem: JAVA",
+		"MISSING: Rule 'build/', Ecosystem: JAVA",
+	}
+
+	suggestions := ignore.MissingRules(RUST, JAVA)
+
+	logs := make([]string, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		logs = append(logs, suggestion.Log())
+	}
+
+	if !reflect.DeepEqual(logs, expected) {
+		t.Errorf("expected %v, got %v", expected, logs)
+	}
+}
+
+func TestApplySuggestions(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		ecosystems []Ecosystem
+		expected   string
+		results    []string
+	}{
+		{
+			name:       "Pass-LabelledSections",
+			content:    "*.md",
+			ecosystems: []Ecosystem{RUST, DOTNET},
+			expected:   "*.md\n\n# Rust\ntarget/\n**/*.rs.bk\n*.pdb\n\n# .NET\nbin/\nobj/\n*.user\n*.suo\n*.rsuser\n.vs/\nTestResults/\n*.nupkg",
+			results: []string{
+				"ADDED: Rule 'target/', Reason: REQUESTED",
+				"ADDED: Rule '**/*.rs.bk', Reason: REQUESTED",
+				"ADDED: Rule '*.pdb', Reason: REQUESTED",
+				"ADDED: Rule 'bin/', Reason: REQUESTED",
+				"ADDED: Rule 'obj/', Reason: REQUESTED",
+				"ADDED: Rule '*.user', Reason: REQUESTED",
+				"ADDED: Rule '*.suo', Reason: REQUESTED",
+				"ADDED: Rule '*.rsuser', Reason: REQUESTED",
+				"ADDED: Rule '.vs/', Reason: REQUESTED",
+				"ADDED: Rule 'TestResults/', Reason: REQUESTED",
+				"ADDED: Rule '*.nupkg', Reason: REQUESTED",
+			},
+		},
+		{
+			name:       "Pass-PresentRulesSkipped",
+			content:    "target/\n**/*.rs.bk\n*.pdb",
+			ecosystems: []Ecosystem{RUST},
+			expected:   "target/\n**/*.rs.bk\n*.pdb",
+			results:    []string{},
+		},
+		{
+			name:       "Pass-CoveredRuleUnchanged",
+			content:    "*.log\n.terraform/\n*.tfstate\n*.tfstate.*\noverride.tf\noverride.tf.json\n*_override.tf\n*_override.tf.json\n.terraformrc\nterraform.rc",
+			ecosystems: []Ecosystem{TERRAFORM},
+			expected:   "*.log\n.terraform/\n*.tfstate\n*.tfstate.*\noverride.tf\noverride.tf.json\n*_override.tf\n*_override.tf.json\n.terraformrc\nterraform.rc",
+			results: []string{
+				"UNCHANGED: Rule 'crash.log', Reason: REQUESTED",
+				"UNCHANGED: Rule 'crash.*.log', Reason: REQUESTED",
+			},
+		},
+		{
+			name:       "Pass-NegatedRuleNeedsReview",
+			content:    "!target/\n**/*.rs.bk\n*.pdb",
+			ecosystems: []Ecosystem{RUST},
+			expected:   "!target/\n**/*.rs.bk\n*.pdb",
+			results:    []string{"REVIEW_RECOMMENDED: Rule 'target/', Reason: FIX_UNKNOWN"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore := NewIgnoreFile()
+			checkErrors("", Parse(tc.content, &ignore), t)
+
+			results, err := ignore.ApplySuggestions(ignore.MissingRules(tc.ecosystems...))
+			checkErrors("", err, t)
+
+			logs := make([]string, 0, len(results))
+			for _, result := range results {
+				logs = append(logs, result.Log())
+			}
+
+			if !reflect.DeepEqual(logs, tc.results) {
+				t.Errorf("expected results %v, got %v", tc.results, logs)
+			}
+
+			if rendered := Render(&ignore, RenderOptions{}); rendered != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, rendered)
+			}
+		})
+	}
+}
@@ -52,7 +52,13 @@ func (f FileRepository) Load(path string, ignoreFile *IgnoreFile) error {
 
 	defer file.Close()
 
-	return LoadFile(file, ignoreFile)
+	if err := LoadFile(file, ignoreFile); err != nil {
+		return err
+	}
+
+	ignoreFile.SetSource(path)
+
+	return nil
 }
 
 // Save writes an IgnoreFile to the specified path using the repository's rendering options.
@@ -57,8 +57,9 @@ func LoadFile(reader io.Reader, ignoreFile *IgnoreFile) error {
 //   - writer: Any io.Writer where the ignore file content should be written.
 //   - ignoreFile: A pointer to the IgnoreFile instance to write.
 //   - opts: The rendering options that control output formatting:
-//     TrailingNewLine adds a newline character at the end if true.
-//     HeaderComment adds a comment line at the top (prefixed with "# ") if non-empty.
+//     TrailingNewLine adds a line break at the end if true.
+//     HeaderComment and FooterComment add a comment at the top and bottom if non-empty.
+//     LineEnding and ByteOrderMark control the encoding, see Render.
 //
 // Returns an error if writing to the writer fails.
 //
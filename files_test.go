@@ -48,3 +48,126 @@ func TestRoundTripFile(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		options  RenderOptions
+		expected string
+	}{
+		{
+			name:     "Pass-Default",
+			content:  "*.log\nbuild/\n",
+			options:  RenderOptions{TrailingNewLine: true},
+			expected: "*.log\nbuild/\n",
+		},
+		{
+			name:     "Pass-CRLF",
+			content:  "*.log\nbuild/\n",
+			options:  RenderOptions{TrailingNewLine: true, LineEnding: CRLF},
+			expected: "*.log\r\nbuild/\r\n",
+		},
+		{
+			name:     "Pass-PreserveCRLF",
+			content:  "*.log\r\nbuild/\r\n",
+			options:  RenderOptions{TrailingNewLine: true, LineEnding: PRESERVE_LINE_ENDING},
+			expected: "*.log\r\nbuild/\r\n",
+		},
+		{
+			name:     "Pass-PreserveLF",
+			content:  "*.log\nbuild/\n",
+			options:  RenderOptions{TrailingNewLine: true, LineEnding: PRESERVE_LINE_ENDING},
+			expected: "*.log\nbuild/\n",
+		},
+		{
+			name:     "Pass-CRLFInputRendersLF",
+			content:  "*.log\r\nbuild/\r\n",
+			options:  RenderOptions{TrailingNewLine: true},
+			expected: "*.log\nbuild/\n",
+		},
+		{
+			name:     "Pass-MultiLineHeader",
+			content:  "*.log\n",
+			options:  RenderOptions{HeaderComment: "Generated by gignore\n\nDo not edit"},
+			expected: "# Generated by gignore\n#\n# Do not edit\n\n*.log",
+		},
+		{
+			name:     "Pass-Footer",
+			content:  "*.log\n",
+			options:  RenderOptions{FooterComment: "End of generated rules"},
+			expected: "*.log\n\n# End of generated rules",
+		},
+		{
+			name:     "Pass-HeaderNotRepeated",
+			content:  "# Generated by gignore\n#\n# Do not edit\n\n*.log\n",
+			options:  RenderOptions{HeaderComment: "Generated by gignore\n\nDo not edit", TrailingNewLine: true},
+			expected: "# Generated by gignore\n#\n# Do not edit\n\n*.log\n",
+		},
+		{
+			name:     "Pass-FooterNotRepeated",
+			content:  "*.log\n\n# Notes\n\n# End of generated rules\n",
+			options:  RenderOptions{FooterComment: "End of generated rules", TrailingNewLine: true},
+			expected: "*.log\n\n# Notes\n\n# End of generated rules\n",
+		},
+		{
+			name:     "Pass-FooterOnly",
+			content:  "",
+			options:  RenderOptions{FooterComment: "Nothing to ignore"},
+			expected: "# Nothing to ignore",
+		},
+		{
+			name:     "Pass-ByteOrderMark",
+			content:  "*.log\n",
+			options:  RenderOptions{ByteOrderMark: true, LineEnding: CRLF, TrailingNewLine: true},
+			expected: "\uFEFF*.log\r\n",
+		},
+		{
+			name:     "Pass-PreserveCR",
+			content:  "*.log\rbuild/\r",
+			options:  RenderOptions{TrailingNewLine: true, LineEnding: PRESERVE_LINE_ENDING},
+			expected: "*.log\rbuild/\r",
+		},
+		{
+			name:     "Pass-MixedLineEndings",
+			content:  "*.log\r\nbuild/\n*.tmp\r",
+			options:  RenderOptions{TrailingNewLine: true, LineEnding: PRESERVE_LINE_ENDING},
+			expected: "*.log\r\nbuild/\r\n*.tmp\r\n",
+		},
+		{
+			name:     "Pass-StripsByteOrderMark",
+			content:  "\uFEFF*.log\n",
+			options:  RenderOptions{TrailingNewLine: true},
+			expected: "*.log\n",
+		},
+		{
+			name:     "Pass-PreserveByteOrderMark",
+			content:  "\uFEFF*.log\r\n",
+			options:  RenderOptions{TrailingNewLine: true, LineEnding: PRESERVE_LINE_ENDING, PreserveByteOrderMark: true},
+			expected: "\uFEFF*.log\r\n",
+		},
+		{
+			name:     "Pass-PreserveWithoutByteOrderMark",
+			content:  "*.log\n",
+			options:  RenderOptions{TrailingNewLine: true, PreserveByteOrderMark: true},
+			expected: "*.log\n",
+		},
+		{
+			name:     "Pass-ManagedBlockCRLF",
+			content:  "custom/\r\n# BEGIN gignore:managed\r\n*.log\r\n# END gignore:managed\r\n",
+			options:  RenderOptions{TrailingNewLine: true, LineEnding: PRESERVE_LINE_ENDING, FooterComment: "Managed"},
+			expected: "custom/\r\n# BEGIN gignore:managed\r\n*.log\r\n\r\n# Managed\r\n# END gignore:managed\r\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignoreFile := NewIgnoreFile()
+			checkErrors("", Parse(tc.content, &ignoreFile), t)
+
+			if rendered := Render(&ignoreFile, tc.options); rendered != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, rendered)
+			}
+		})
+	}
+}
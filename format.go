@@ -0,0 +1,209 @@
+package gignore
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Format rewrites ignore files into a canonical form, the way gofmt does for Go source:
+//   - Rules are spelled one way: duplicate slashes are collapsed, "**/**" becomes "**",
+//     "/**/x" becomes "**/x", "x/**/*" becomes "x/**" and "**/*.ext" becomes "*.ext"
+//   - Rules are sorted within each run of consecutive rules that share a section and an action.
+//     Only the order of rules with different actions decides which rule wins, so sorting such
+//     a run never changes what is ignored. Rules are never moved across a negation.
+//   - Sections are separated by exactly one blank line and comments start with "# "
+//   - Comments are never dropped: the header and footer of the file stay in place, and other
+//     comments move with the rule below them
+//   - Trailing spaces are stripped unless they are escaped with a backslash, as git does. Trailing
+//     tabs are part of the pattern and are kept
+//
+// "x/**/" is not rewritten to "x/**": it only matches directories below x, not files directly in it.
+// Patterns with "./" segments never match and are left as they are, since rewriting "./x" to "/x"
+// would change what the file ignores. The linter reports a leading "./" as DOT_SLASH_PREFIX.
+
+// MARK: Rules
+
+// Returns the canonical spelling of a pattern, without the "!" prefix
+func canonicalPattern(pattern string) string {
+	for strings.Contains(pattern, "//") {
+		pattern = strings.ReplaceAll(pattern, "//", "/")
+	}
+
+	for strings.Contains(pattern, "**/**") {
+		pattern = strings.ReplaceAll(pattern, "**/**", "**")
+	}
+
+	if strings.HasPrefix(pattern, "/**/") {
+		pattern = strings.TrimPrefix(pattern, "/") // "**/" is anchored already
+	}
+
+	if strings.HasSuffix(pattern, "/**/*") {
+		pattern = strings.TrimSuffix(pattern, "/*") // "**" already matches everything below
+	}
+
+	if rest, ok := strings.CutPrefix(pattern, "**/"); ok && isExtensionPattern(rest) {
+		pattern = rest // "*.ext" matches at any depth
+	}
+
+	return pattern
+}
+
+// Returns the canonical form of a rule, or the rule itself if it is canonical already
+func canonicalRule(rule Ruler) Ruler {
+	pattern := canonicalPattern(rule.Pattern())
+	if pattern == rule.Pattern() {
+		return rule
+	}
+
+	canonical, err := parseRule(rule.Action().Prefix() + pattern)
+	if err != nil {
+		return rule
+	}
+
+	return canonical
+}
+
+// MARK: Comments
+
+// Writes comments with a single space after "#", e.g. "#Build output" and "#   Build output"
+// become "# Build output". Comments like "#!" or "#---" are left alone
+func canonicalComment(comment string) string {
+	text, ok := strings.CutPrefix(strings.TrimSpace(comment), "#")
+	if !ok {
+		return comment
+	}
+
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	if trimmed == "" {
+		return "#"
+	}
+
+	if first := []rune(trimmed)[0]; unicode.IsLetter(first) || unicode.IsDigit(first) {
+		return "# " + trimmed
+	}
+
+	return "#" + text
+}
+
+func canonicalComments(comments []string) []string {
+	if comments == nil {
+		return nil
+	}
+
+	canonical := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		canonical = append(canonical, canonicalComment(comment))
+	}
+
+	return canonical
+}
+
+// MARK: Format
+
+// Format rewrites the IgnoreFile into canonical form: rules are respelled, runs of rules that
+// share a section and an action are sorted, and comments are normalized. What the file
+// ignores does not change. Rules outside of a managed block are left as they are.
+//
+// Rules that become identical once respelled are kept, so FixConflicts can report and remove
+// the duplicates as it would for any other file.
+//
+// Example:
+//
+//	var ignoreFile IgnoreFile
+//	Parse("build//\n**/*.tmp\n*.log\n*.bak\n", &ignoreFile)
+//
+//	ignoreFile.Format()
+//	fmt.Print(Render(&ignoreFile, RenderOptions{TrailingNewLine: true}))
+//	// Output:
+//	// *.bak
+//	// *.log
+//	// *.tmp
+//	// build/
+func (f *IgnoreFile) Format() {
+	working := f.clone()
+
+	for idx, rule := range working.rules {
+		canonical := canonicalRule(rule)
+		if rulesEqual(canonical, rule) {
+			continue
+		}
+
+		working.rules[idx] = canonical
+	}
+
+	working.padMeta()
+	for start := 0; start < len(working.rules); {
+		end := start + 1
+		for end < len(working.rules) && working.sortsWith(start, end) {
+			end++
+		}
+
+		sort.Stable(ruleRun{rules: working.rules[start:end], meta: working.meta[start:end]})
+
+		start = end
+	}
+
+	for idx, meta := range working.meta {
+		meta.comments = canonicalComments(meta.comments)
+		meta.detached = canonicalComments(meta.detached)
+		working.meta[idx] = meta
+	}
+
+	working.directives = canonicalComments(working.directives)
+	working.header = canonicalComments(working.header)
+	working.footer = canonicalComments(working.footer)
+
+	*f = working
+}
+
+// Sorts a run of rules by their rendered form, moving each rule's metadata with it
+type ruleRun struct {
+	rules []Ruler
+	meta  []ruleMeta
+}
+
+func (r ruleRun) Len() int           { return len(r.rules) }
+func (r ruleRun) Less(i, j int) bool { return r.rules[i].Render() < r.rules[j].Render() }
+func (r ruleRun) Swap(i, j int) {
+	r.rules[i], r.rules[j] = r.rules[j], r.rules[i]
+	r.meta[i], r.meta[j] = r.meta[j], r.meta[i]
+}
+
+// Reports whether the rules at two indexes can be reordered relative to each other without
+// changing what is ignored
+func (f IgnoreFile) sortsWith(left, right int) bool {
+	return f.rules[left].Action() == f.rules[right].Action() && f.sectionAt(left) == f.sectionAt(right)
+}
+
+// Format parses ignore file content, formats it and renders it again. See IgnoreFile.Format.
+//
+// Parameters:
+//   - content: The ignore file content to format.
+//   - options: The rendering options. If TrailingNewLine is false, the trailing newline of the
+//     content is kept as it is; set it to true to make sure the output ends with one.
+//
+// Returns the formatted content and an error if the content cannot be parsed.
+//
+// Example:
+//
+//	formatted, err := Format(string(content), RenderOptions{TrailingNewLine: true})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	if formatted != string(content) {
+//	    fmt.Println(".gitignore is not formatted")
+//	}
+func Format(content string, options RenderOptions) (string, error) {
+	ignoreFile := NewIgnoreFile()
+	if err := Parse(content, &ignoreFile); err != nil {
+		return "", err
+	}
+
+	ignoreFile.Format()
+
+	options.TrailingNewLine = options.TrailingNewLine || strings.HasSuffix(content, "\n")
+
+	return Render(&ignoreFile, options), nil
+}
@@ -0,0 +1,152 @@
+package gignore
+
+import "testing"
+
+func TestCanonicalPattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{pattern: "build/", expected: "build/"},
+		{pattern: "build//", expected: "build/"},
+		{pattern: "src//gen///out", expected: "src/gen/out"},
+		{pattern: "./tmp", expected: "./tmp"},
+		{pattern: "src/./gen", expected: "src/./gen"},
+		{pattern: "../shared", expected: "../shared"},
+		{pattern: "a/**/**/b", expected: "a/**/b"},
+		{pattern: "/**/node_modules", expected: "**/node_modules"},
+		{pattern: "build/**/*", expected: "build/**"},
+		{pattern: "build/**/", expected: "build/**/"},
+		{pattern: "**/*.log", expected: "*.log"},
+		{pattern: "**/*.test.js", expected: "*.test.js"},
+		{pattern: "**/gen/*.go", expected: "**/gen/*.go"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.pattern, func(t *testing.T) {
+			if canonical := canonicalPattern(tc.pattern); canonical != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, canonical)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		options  RenderOptions
+		expected string
+	}{
+		{
+			name:     "Pass-Formatted",
+			content:  "*.log\nbuild/\n",
+			expected: "*.log\nbuild/\n",
+		},
+		{
+			name:     "Pass-RuleSpelling",
+			content:  "build//\n**/*.bak\n",
+			expected: "*.bak\nbuild/\n",
+		},
+		{
+			// "./tmp" never matches, "/tmp" would ignore tmp, so formatting leaves it for lint
+			name:     "Pass-DotSlashUnchanged",
+			content:  "./tmp\nsrc/./gen\n",
+			expected: "./tmp\nsrc/./gen\n",
+		},
+		{
+			name:     "Pass-SortsWithinRuns",
+			content:  "*.log\n*.bak\n!keep.log\n!keep.bak\nz.txt\na.txt\n",
+			expected: "*.bak\n*.log\n!keep.bak\n!keep.log\na.txt\nz.txt\n",
+		},
+		{
+			name:     "Pass-NeverSortsAcrossNegation",
+			content:  "*.log\n!debug.log\n*.bak\n",
+			expected: "*.log\n!debug.log\n*.bak\n",
+		},
+		{
+			name:     "Pass-SortsWithinSections",
+			content:  "# Go\n*.test\n*.out\n\n\n\n# IDE\n.vscode/\n.idea/\n",
+			expected: "# Go\n*.out\n*.test\n\n# IDE\n.idea/\n.vscode/\n",
+		},
+		{
+			name:     "Pass-CommentsMoveWithRules",
+			content:  "#Temporary files\ntmp/\n#   Logs\n*.log\n",
+			expected: "# Temporary files\n# Logs\n*.log\ntmp/\n",
+		},
+		{
+			name:     "Pass-AttachedComments",
+			content:  "*.log\n#Keep the audit log\n!audit.log\n",
+			expected: "*.log\n# Keep the audit log\n!audit.log\n",
+		},
+		{
+			name:     "Pass-KeepsMissingTrailingNewline",
+			content:  "b.txt\na.txt",
+			expected: "a.txt\nb.txt",
+		},
+		{
+			name:     "Pass-EnforcesTrailingNewline",
+			content:  "b.txt\na.txt",
+			options:  RenderOptions{TrailingNewLine: true},
+			expected: "a.txt\nb.txt\n",
+		},
+		{
+			name:     "Pass-KeepsHeaderAndFooter",
+			content:  "# Created by some generator\n# see LICENSE\n\n*.log\n\n# trailing note\n",
+			expected: "# Created by some generator\n# see LICENSE\n\n*.log\n\n# trailing note\n",
+		},
+		{
+			name:     "Pass-KeepsDetachedComments",
+			content:  "z.txt\n\n#Loose note\n\na.txt\n",
+			expected: "# Loose note\n\na.txt\nz.txt\n",
+		},
+		{
+			name:     "Pass-KeepsEscapedTrailingSpace",
+			content:  "foo\\ \nbar  \n",
+			expected: "bar\nfoo\\ \n",
+		},
+		{
+			name:     "Pass-ManagedBlock",
+			content:  "z.txt\na.txt\n# BEGIN gignore:managed\nb//\na/\n# END gignore:managed\n",
+			expected: "z.txt\na.txt\n# BEGIN gignore:managed\na/\nb/\n# END gignore:managed\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			formatted, err := Format(tc.content, tc.options)
+			checkErrors("", err, t)
+
+			if formatted != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, formatted)
+			}
+
+			again, err := Format(formatted, tc.options)
+			checkErrors("", err, t)
+
+			if again != formatted {
+				t.Errorf("expected formatting to be idempotent, got %q", again)
+			}
+		})
+	}
+}
+
+func TestFormatKeepsMetadata(t *testing.T) {
+	ignoreFile := NewIgnoreFile()
+	checkErrors("", Parse("# Build\n# Output of make\nbuild//\n", &ignoreFile), t)
+
+	ignoreFile.Format()
+
+	rule := DirectoryRule{name: "build", mode: DIRECTORY, act: INCLUDE}
+	if section := ignoreFile.SectionOf(rule); section != "Build" {
+		t.Errorf("expected section Build, got %q", section)
+	}
+
+	if comments := ignoreFile.metaFor(rule).comments; len(comments) != 1 || comments[0] != "# Output of make" {
+		t.Errorf("expected the comment to move with the rule, got %v", comments)
+	}
+
+	if len(ignoreFile.meta) != 1 {
+		t.Errorf("expected metadata for the one rule, got %v", ignoreFile.meta)
+	}
+}
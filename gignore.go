@@ -3,6 +3,7 @@ package gignore
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -59,6 +60,30 @@ func (a Action) Validate() error {
 	}
 }
 
+// MarshalText encodes the Action as "include" or "exclude", the form ActionFromString accepts.
+func (a Action) MarshalText() ([]byte, error) {
+	switch a {
+	case INCLUDE:
+		return []byte("include"), nil
+	case EXCLUDE:
+		return []byte("exclude"), nil
+	default:
+		return nil, invalidActionError
+	}
+}
+
+// UnmarshalText decodes an Action encoded by MarshalText.
+func (a *Action) UnmarshalText(text []byte) error {
+	action, err := ActionFromString(string(text))
+	if err != nil {
+		return err
+	}
+
+	*a = action
+
+	return nil
+}
+
 func (a Action) Prefix() string {
 	switch a {
 	case INCLUDE:
@@ -84,7 +109,7 @@ type FileRule struct {
 }
 
 func validatePath(path string) (string, error) {
-	path = strings.TrimSpace(path)
+	path = trimWhitespace(path)
 	if path == "" {
 		return "", emptyPathError
 	}
@@ -144,7 +169,7 @@ type ExtensionRule struct {
 }
 
 func validateExtension(ext string) (string, error) {
-	ext = strings.TrimSpace(ext)
+	ext = trimWhitespace(ext)
 	ext = strings.TrimPrefix(ext, "*.")
 	if ext == "" {
 		return "", emptyExtensionError
@@ -248,6 +273,36 @@ func (d DirectoryMode) Validate() error {
 	}
 }
 
+// MarshalText encodes the DirectoryMode in the form DirectoryModeFromString accepts, e.g. "recursive".
+func (d DirectoryMode) MarshalText() ([]byte, error) {
+	switch d {
+	case DIRECTORY:
+		return []byte("directory"), nil
+	case CHILDREN:
+		return []byte("children"), nil
+	case RECURSIVE:
+		return []byte("recursive"), nil
+	case ANYWHERE:
+		return []byte("anywhere"), nil
+	case ROOT_ONLY:
+		return []byte("root_only"), nil
+	default:
+		return nil, invalidDirectoryModeError
+	}
+}
+
+// UnmarshalText decodes a DirectoryMode encoded by MarshalText.
+func (d *DirectoryMode) UnmarshalText(text []byte) error {
+	mode, err := DirectoryModeFromString(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = mode
+
+	return nil
+}
+
 func (d DirectoryMode) Prefix() string {
 	switch d {
 	case ANYWHERE:
@@ -273,7 +328,7 @@ func (d DirectoryMode) Suffix() string {
 }
 
 func validateDirectoryName(name string) (string, error) {
-	name = strings.TrimSpace(name)
+	name = trimWhitespace(name)
 	name = strings.TrimPrefix(name, "/") // strip leading slash
 	name = strings.TrimSuffix(name, "/") // strip trailing slash
 	if name == "" {
@@ -369,7 +424,7 @@ type GlobRule struct {
 }
 
 func validateGlobPattern(pattern string) (string, error) {
-	pattern = strings.TrimSpace(pattern)
+	pattern = trimWhitespace(pattern)
 	if pattern == "" {
 		return "", emptyGlobPatternError
 	}
@@ -434,18 +489,162 @@ func rulesEqual(left, right Ruler) bool {
 	return left.Pattern() == right.Pattern() && left.Action() == right.Action()
 }
 
+// ruleMeta holds information about a rule that is not part of the rule itself
+// Metadata of one copy of a rule, copies of a repeated rule each keep their own
+type ruleMeta struct {
+	line     int // line the rule was parsed from, 0 if it was not parsed
+	section  string
+	comments []string // comment lines directly above the rule
+	detached []string // comment lines above the rule that are separated from it by a blank line
+}
+
 type IgnoreFile struct {
-	rules []Ruler
+	rules         []Ruler
+	meta          []ruleMeta // metadata of each rule, parallel to rules
+	block         *managedBlock
+	directives    []string         // suppression directives that are not attached to a rule
+	header        []string         // comments above the first rule that are not attached to it
+	footer        []string         // comments below the last rule
+	templates     []TemplateRecord // templates the file was generated from
+	lineEnding    LineEnding       // line break style detected by Parse
+	byteOrderMark bool             // whether the parsed content started with a byte order mark
+	source        string           // ignore file the content was loaded from, if known
 }
 
 func NewIgnoreFile() IgnoreFile {
 	return IgnoreFile{rules: make([]Ruler, 0)}
 }
 
+// Copies the IgnoreFile so modifications can be discarded if they fail partway
+func (f IgnoreFile) clone() IgnoreFile {
+	rules := make([]Ruler, len(f.rules))
+	copy(rules, f.rules)
+
+	clone := IgnoreFile{
+		rules:         rules,
+		meta:          slices.Clone(f.meta),
+		directives:    append([]string{}, f.directives...),
+		header:        append([]string{}, f.header...),
+		footer:        append([]string{}, f.footer...),
+		templates:     append([]TemplateRecord{}, f.templates...),
+		lineEnding:    f.lineEnding,
+		byteOrderMark: f.byteOrderMark,
+		source:        f.source,
+	}
+	if f.block != nil {
+		block := *f.block
+		clone.block = &block
+	}
+
+	return clone
+}
+
+// Rules with the same pattern and action are the same rule, which matches how rules are compared
+func ruleKey(rule Ruler) string {
+	return rule.Render()
+}
+
+// Returns the metadata of the managed rule at idx
+func (f IgnoreFile) metaAt(idx int) ruleMeta {
+	if idx < len(f.meta) {
+		return f.meta[idx]
+	}
+
+	return ruleMeta{}
+}
+
+func (f *IgnoreFile) setMetaAt(idx int, meta ruleMeta) {
+	f.padMeta()
+	f.meta[idx] = meta
+}
+
+// Returns the metadata of the first copy of a rule, inside or outside of a managed block
+func (f IgnoreFile) metaFor(rule Ruler) ruleMeta {
+	metas := f.allMeta()
+	for idx, existing := range f.allRules() {
+		if rulesEqual(existing, rule) {
+			return metas[idx]
+		}
+	}
+
+	return ruleMeta{}
+}
+
 // Adds a rule - used in parser
 // Skips all validation! Only use when you can relax that constraint
-func (f *IgnoreFile) addRule(rule Ruler) {
-	f.rules = append(f.rules, rule)
+func (f *IgnoreFile) addRule(rule Ruler, meta ruleMeta) {
+	f.insertAt(len(f.rules), rule, meta)
+}
+
+// Inserts a rule at idx, keeping the metadata in step with the rules
+func (f *IgnoreFile) insertAt(idx int, rule Ruler, meta ruleMeta) {
+	f.padMeta()
+	f.rules = slices.Insert(f.rules, idx, rule)
+	f.meta = slices.Insert(f.meta, idx, meta)
+}
+
+// Removes the rule at idx along with its metadata
+func (f *IgnoreFile) removeAt(idx int) {
+	f.padMeta()
+	f.rules = slices.Delete(f.rules, idx, idx+1)
+	f.meta = slices.Delete(f.meta, idx, idx+1)
+}
+
+// Removes the rule at idx without losing the comments around it: the comments directly above it
+// move onto the rule at into, and the detached comments above it stay in place above the next
+// rule. Pass -1 as into to drop the comments with the rule
+func (f *IgnoreFile) removeKeepingComments(idx, into int) {
+	meta := f.metaAt(idx)
+
+	if into != -1 && len(meta.comments) > 0 {
+		target := f.metaAt(into)
+		if into < idx {
+			target.comments = append(slices.Clone(target.comments), meta.comments...)
+		} else {
+			target.comments = append(slices.Clone(meta.comments), target.comments...)
+		}
+		f.setMetaAt(into, target)
+	}
+
+	if idx+1 < len(f.rules) {
+		next := f.metaAt(idx + 1)
+		next.detached = joinCommentBlocks(meta.detached, next.detached)
+		f.setMetaAt(idx+1, next)
+	} else {
+		f.footer = joinCommentBlocks(meta.detached, f.footer)
+	}
+
+	f.removeAt(idx)
+}
+
+// Joins two blocks of comments with a blank line between them
+func joinCommentBlocks(first, second []string) []string {
+	switch {
+	case len(first) == 0:
+		return second
+	case len(second) == 0:
+		return first
+	default:
+		return append(append(slices.Clone(first), ""), second...)
+	}
+}
+
+// Rules that were set without metadata, e.g. IgnoreFile{rules: ...}, were not parsed
+func (f *IgnoreFile) padMeta() {
+	for len(f.meta) < len(f.rules) {
+		f.meta = append(f.meta, ruleMeta{})
+	}
+}
+
+// Reports whether the file contains a rule, inside or outside of a managed block
+func (f IgnoreFile) containsRule(target Ruler) bool {
+	for _, rule := range f.allRules() {
+		if rulesEqual(rule, target) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (f *IgnoreFile) findRuleIndex(target Ruler) int {
@@ -467,9 +666,19 @@ func (f *IgnoreFile) ruleShouldComeBefore(newRule, existing Ruler) bool {
 }
 
 func (f *IgnoreFile) fixConflict(conflict Conflict) (Result, error) {
+	if !f.canFixConflict(conflict) {
+		// Rules outside of a managed block and rules suppressed for the conflict type are never modified
+		return Result{
+			Rule:     conflict.Right,
+			Result:   REVIEW_RECOMMENDED,
+			Reason:   FIX_UNKNOWN,
+			Position: conflict.RightPosition,
+		}, nil
+	}
+
 	switch conflict.ConflictType {
 	case REDUNDANT_RULE:
-		return f.deleteMatchingRule(conflict.Left, AUTOMATED_FIX)
+		return f.removeRedundantCopy(conflict.Left)
 	case UNREACHABLE_RULE:
 		leftIdx := f.findRuleIndex(conflict.Left)
 		rightIdx := f.findRuleIndex(conflict.Right)
@@ -483,9 +692,10 @@ func (f *IgnoreFile) fixConflict(conflict Conflict) (Result, error) {
 		return f.MoveRule(conflict.Right, conflict.Left, AFTER, AUTOMATED_FIX)
 	case SEMANTIC_CONFLICT:
 		return Result{
-			Rule:   conflict.Left,
-			Result: REVIEW_RECOMMENDED,
-			Reason: FIX_UNKNOWN,
+			Rule:     conflict.Left,
+			Result:   REVIEW_RECOMMENDED,
+			Reason:   FIX_UNKNOWN,
+			Position: conflict.LeftPosition,
 		}, nil
 	case INEFFECTIVE_RULE:
 		return f.MoveRule(conflict.Left, conflict.Right, AFTER, AUTOMATED_FIX)
@@ -494,6 +704,64 @@ func (f *IgnoreFile) fixConflict(conflict Conflict) (Result, error) {
 	}
 }
 
+// Removes the first managed copy of a repeated rule. Its comments move onto the next managed copy,
+// so a comment is not lost because it was written above the copy that is removed
+func (f *IgnoreFile) removeRedundantCopy(target Ruler) (Result, error) {
+	idx := f.findRuleIndex(target)
+	if idx == -1 {
+		return Result{}, ruleNotFoundError
+	}
+
+	into := -1
+	for i := idx + 1; i < len(f.rules); i++ {
+		if rulesEqual(f.rules[i], target) {
+			into = i
+			break
+		}
+	}
+
+	position := f.managedPosition(idx)
+	f.removeKeepingComments(idx, into)
+
+	return Result{
+		Rule:     target,
+		Result:   REMOVED,
+		Reason:   AUTOMATED_FIX,
+		Position: position,
+	}, nil
+}
+
+func (f *IgnoreFile) countCopies(target Ruler) int {
+	count := 0
+	for _, rule := range f.allRules() {
+		if rulesEqual(rule, target) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func conflictKey(conflict Conflict) string {
+	return string(conflict.ConflictType) + "\x00" + ruleKey(conflict.Left) + "\x00" + ruleKey(conflict.Right)
+}
+
+func (f *IgnoreFile) canFixConflict(conflict Conflict) bool {
+	if f.isSuppressed(conflict, f.fileSuppressions()) {
+		return false
+	}
+
+	switch conflict.ConflictType {
+	case REDUNDANT_RULE:
+		// Either copy can be removed, deleteMatchingRule only looks at managed rules
+		return f.isManaged(conflict.Left)
+	case UNREACHABLE_RULE, INEFFECTIVE_RULE:
+		return f.isManaged(conflict.Left) && f.isManaged(conflict.Right)
+	default:
+		return true
+	}
+}
+
 // FixConflicts attempts to automatically resolve conflicts within the IgnoreFile by running
 // multiple passes of conflict detection and resolution. The method will stop early if no
 // conflicts are found in a given pass.
@@ -511,6 +779,9 @@ func (f *IgnoreFile) fixConflict(conflict Conflict) (Result, error) {
 //  3. Repeating until no conflicts remain or maxPasses is reached
 //  4. Recording each fix operation in the returned Results
 //
+// Conflicts that cannot be fixed automatically, such as those between rules outside of a managed
+// block, are reported once as REVIEW_RECOMMENDED however many passes find them.
+//
 // Example:
 //
 //	fixes, err := ignoreFile.FixConflicts(5)
@@ -523,6 +794,7 @@ func (f *IgnoreFile) fixConflict(conflict Conflict) (Result, error) {
 //	}
 func (f *IgnoreFile) FixConflicts(maxPasses int) ([]Result, error) {
 	fixLogs := make([]Result, 0)
+	reviewed := make(map[string]bool)
 
 	for range maxPasses {
 		conflicts := f.FindConflicts()
@@ -532,11 +804,26 @@ func (f *IgnoreFile) FixConflicts(maxPasses int) ([]Result, error) {
 		}
 
 		for _, conflict := range conflicts {
+			// A rule repeated three or more times is reported once per pair, but only the extra copies may go
+			if conflict.ConflictType == REDUNDANT_RULE && f.countCopies(conflict.Left) < 2 {
+				continue
+			}
+
+			// Conflicts left for review are found again on every pass, only report them once
+			key := conflictKey(conflict)
+			if reviewed[key] {
+				continue
+			}
+
 			description, err := f.fixConflict(conflict)
 			if err != nil {
 				return fixLogs, err
 			}
 
+			if description.Result == REVIEW_RECOMMENDED {
+				reviewed[key] = true
+			}
+
 			fixLogs = append(fixLogs, description)
 		}
 	}
@@ -545,7 +832,24 @@ func (f *IgnoreFile) FixConflicts(maxPasses int) ([]Result, error) {
 }
 
 func (f *IgnoreFile) addRuleWithConflictResolution(rule Ruler) ([]Result, error) {
-	idealInsertionPoint := len(f.rules) // default insertion point to the end
+	idx, err := f.insertRule(rule, len(f.rules)) // default insertion point to the end
+	if err != nil {
+		return make([]Result, 0), err
+	}
+
+	f.adoptNeighborSection(idx)
+
+	return f.resolveAddition(rule)
+}
+
+// Inserts a rule at the first position that avoids conflicts, falling back to defaultInsertionPoint.
+// Returns the index the rule was inserted at.
+func (f *IgnoreFile) insertRule(rule Ruler, defaultInsertionPoint int) (int, error) {
+	if err := f.checkUnmanagedConflicts(rule); err != nil {
+		return -1, err
+	}
+
+	idealInsertionPoint := defaultInsertionPoint
 
 	for i, existing := range f.rules {
 		// When adding a rule, check conflicts with each existing rule
@@ -555,13 +859,15 @@ func (f *IgnoreFile) addRuleWithConflictResolution(rule Ruler) ([]Result, error)
 		if conflict, found := checkConflict(existing, rule, intervening); found {
 			switch conflict.ConflictType {
 			case SEMANTIC_CONFLICT:
-				return make([]Result, 0), semanticConflictError
+				return -1, semanticConflictError
 			case REDUNDANT_RULE:
-				return make([]Result, 0), redundantRuleError
+				return -1, redundantRuleError
 			case UNREACHABLE_RULE:
-				return make([]Result, 0), unreachableRuleError
+				return -1, unreachableRuleError
 			case INEFFECTIVE_RULE:
-				idealInsertionPoint = i
+				if i < idealInsertionPoint {
+					idealInsertionPoint = i
+				}
 			}
 		}
 
@@ -570,8 +876,13 @@ func (f *IgnoreFile) addRuleWithConflictResolution(rule Ruler) ([]Result, error)
 		}
 	}
 
-	f.rules = append(f.rules[:idealInsertionPoint], append([]Ruler{rule}, f.rules[idealInsertionPoint:]...)...)
+	f.insertAt(idealInsertionPoint, rule, ruleMeta{})
 
+	return idealInsertionPoint, nil
+}
+
+// Fixes any conflicts caused by a newly inserted rule and records the addition
+func (f *IgnoreFile) resolveAddition(rule Ruler) ([]Result, error) {
 	addition := Result{
 		Rule:   rule,
 		Result: ADDED,
@@ -590,14 +901,60 @@ func (f *IgnoreFile) addRuleWithConflictResolution(rule Ruler) ([]Result, error)
 	return fixedConflicts, nil
 }
 
+// Rules before a managed block always come before the new rule and rules after it always come
+// after, so they can make it redundant or unreachable even though they can't be changed
+func (f *IgnoreFile) checkUnmanagedConflicts(rule Ruler) error {
+	if f.block == nil {
+		return nil
+	}
+
+	preceding := append(append([]Ruler{}, f.block.beforeRules...), f.rules...)
+	for i, existing := range f.block.beforeRules {
+		if err := unmanagedConflictError(checkConflict(existing, rule, preceding[i+1:])); err != nil {
+			return err
+		}
+	}
+
+	for i, existing := range f.block.afterRules {
+		if err := unmanagedConflictError(checkConflict(rule, existing, f.block.afterRules[:i])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the error for a conflict with a rule outside of a managed block, or nil if the
+// conflict can be left for FixConflicts to report
+func unmanagedConflictError(conflict Conflict, found bool) error {
+	if !found {
+		return nil
+	}
+
+	switch conflict.ConflictType {
+	case SEMANTIC_CONFLICT:
+		return semanticConflictError
+	case REDUNDANT_RULE:
+		return redundantRuleError
+	case UNREACHABLE_RULE:
+		return unreachableRuleError
+	default:
+		return nil
+	}
+}
+
 func (f *IgnoreFile) deleteMatchingRule(target Ruler, reason ActionReason) (Result, error) {
 	for i, rule := range f.rules {
 		if rulesEqual(rule, target) {
-			f.rules = append(f.rules[:i], f.rules[i+1:]...)
+			position := f.managedPosition(i)
+
+			f.removeAt(i)
+
 			return Result{
-				Rule:   target,
-				Result: REMOVED,
-				Reason: reason,
+				Rule:     target,
+				Result:   REMOVED,
+				Reason:   reason,
+				Position: position,
 			}, nil
 		}
 	}
@@ -932,6 +1289,95 @@ func (f *IgnoreFile) DeleteGlob(pattern string, action Action) (Result, error) {
 	return f.deleteMatchingRule(targetRule, REQUESTED)
 }
 
+// EnsurePresent makes sure the IgnoreFile contains the given rule. Unlike the Add methods,
+// a rule that already exists is not treated as an error, which makes the method safe to
+// call repeatedly from automation.
+//
+// Parameters:
+//   - rule: The rule that must be present. Matching uses the rule's pattern and action.
+//
+// Returns a slice of Result and an error. If the rule already exists, including outside of a
+// managed block, the slice contains a single UNCHANGED result. Otherwise the rule is added with automatic conflict detection and
+// resolution, exactly like the Add methods. The error will be non-nil if:
+//   - A semantic conflict or unreachable rule is detected
+//   - Automatic conflict resolution fails
+//
+// Example:
+//
+//	rule, _ := NewExtensionRule("log", INCLUDE)
+//	results, err := ignoreFile.EnsurePresent(rule)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	if results[0].Changed() {
+//	    fmt.Println("rule added")
+//	}
+func (f *IgnoreFile) EnsurePresent(rule Ruler) ([]Result, error) {
+	if f.containsRule(rule) {
+		return []Result{{
+			Rule:     rule,
+			Result:   UNCHANGED,
+			Reason:   REQUESTED,
+			Position: f.PositionOf(rule),
+		}}, nil
+	}
+
+	return f.addRuleWithConflictResolution(rule)
+}
+
+// EnsureAbsent makes sure the IgnoreFile does not contain the given rule. Unlike the Delete
+// methods, a missing rule is not treated as an error. Every copy of the rule is removed.
+//
+// Parameters:
+//   - rule: The rule that must be absent. Matching uses the rule's pattern and action.
+//
+// Returns a Result describing the outcome and an error. The Result is REMOVED if the rule
+// was deleted, describing the first copy removed, or UNCHANGED if the rule was not present.
+// The error is non-nil if a copy of the rule is outside of a managed block, where it can't be
+// removed; the IgnoreFile is then left unchanged.
+//
+// Example:
+//
+//	rule, _ := NewFileRule("todo.md", INCLUDE)
+//	result, err := ignoreFile.EnsureAbsent(rule)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(result.Log())
+func (f *IgnoreFile) EnsureAbsent(rule Ruler) (Result, error) {
+	if !f.containsRule(rule) {
+		return Result{
+			Rule:   rule,
+			Result: UNCHANGED,
+			Reason: REQUESTED,
+		}, nil
+	}
+
+	working := f.clone()
+	var result Result
+
+	for working.findRuleIndex(rule) != -1 {
+		removal, err := working.deleteMatchingRule(rule, REQUESTED)
+		if err != nil {
+			return Result{}, err
+		}
+
+		if result.Rule == nil {
+			result = removal
+		}
+	}
+
+	// Only copies outside of the managed block are left, which gignore may not modify
+	if working.containsRule(rule) {
+		return Result{}, ruleNotFoundError
+	}
+
+	*f = working
+
+	return result, nil
+}
+
 // MARK: Read methods
 func (f IgnoreFile) Rules() []Ruler {
 	return f.rules
@@ -944,7 +1390,11 @@ func (f IgnoreFile) Rules() []Ruler {
 //
 // The conflict detection considers the order of rules and any intervening rules between each
 // pair, as rule precedence affects conflict resolution. Each unique conflict is reported only
-// once by avoiding duplicate comparisons and self-comparisons.
+// once by avoiding duplicate comparisons and self-comparisons. If the IgnoreFile has a managed
+// block, rules outside of the block are included in the analysis.
+//
+// Conflicts silenced by a "# gignore:disable <TYPE>" comment anywhere in the file, or by a
+// "# gignore:ignore-next-line <TYPE>" comment directly above either rule, are not reported.
 //
 // Returns a slice of Conflict containing all detected conflicts. An empty slice indicates
 // no conflicts were found. The conflicts are detected in the order rules appear in the file,
@@ -970,13 +1420,23 @@ func (f IgnoreFile) Rules() []Ruler {
 func (f IgnoreFile) FindConflicts() []Conflict {
 	var conflicts []Conflict
 
-	for i, rule1 := range f.rules {
-		for j, rule2 := range f.rules {
+	suppressed := f.fileSuppressions()
+	rules := f.allRules()
+	lines := f.allLines()
+	for i, rule1 := range rules {
+		for j, rule2 := range rules {
 			if i >= j { // avoid duplicates and self-comparison
 				continue
 			}
 
-			if conflict, found := checkConflict(rule1, rule2, f.rules[i+1:j]); found {
+			if conflict, found := checkConflict(rule1, rule2, rules[i+1:j]); found && !f.isSuppressed(conflict, suppressed) {
+				left, right := lines[i], lines[j]
+				if conflict.Left != rule1 {
+					left, right = right, left // checkConflict put the later rule on the left
+				}
+
+				conflict.LeftPosition = Position{Source: f.source, Line: left}
+				conflict.RightPosition = Position{Source: f.source, Line: right}
 				conflicts = append(conflicts, conflict)
 			}
 		}
@@ -1004,32 +1464,56 @@ func MoveDirectionFromString(direction string) (MoveDirection, error) {
 	}
 }
 
-func (f *IgnoreFile) moveRule(from, to int) error {
+// MarshalText encodes the MoveDirection as "before" or "after", the form MoveDirectionFromString accepts.
+func (d MoveDirection) MarshalText() ([]byte, error) {
+	switch d {
+	case BEFORE:
+		return []byte("before"), nil
+	case AFTER:
+		return []byte("after"), nil
+	default:
+		return nil, invalidateDirectionError
+	}
+}
+
+// UnmarshalText decodes a MoveDirection encoded by MarshalText.
+func (d *MoveDirection) UnmarshalText(text []byte) error {
+	direction, err := MoveDirectionFromString(string(text))
+	if err != nil {
+		return invalidateDirectionError
+	}
+
+	*d = direction
+
+	return nil
+}
+
+// Moves the rule at from to to, and returns the index the rule ends up at
+func (f *IgnoreFile) moveRule(from, to int) (int, error) {
 	if from < 0 || from >= len(f.rules) {
-		return sourceIdxOutOfRangeError
+		return -1, sourceIdxOutOfRangeError
 	}
 
 	if from == to {
-		return nil // No move needed
+		return from, nil // No move needed
 	}
 
-	// Remove rule from current position
-	rule := f.rules[from]
-	f.rules = append(f.rules[:from], f.rules[from+1:]...)
-
-	// Adjust target index if needed (now that we removed one element)
+	// Adjust target index if needed (the rule is removed before it is inserted again)
 	if to > from {
 		to--
 	}
 
 	// Validate adjusted target
-	if to < 0 || to > len(f.rules) {
-		return targetIdxOutofRangeError
+	if to < 0 || to > len(f.rules)-1 {
+		return -1, targetIdxOutofRangeError
 	}
 
-	// Insert at new position
-	f.rules = append(f.rules[:to], append([]Ruler{rule}, f.rules[to:]...)...)
-	return nil
+	// Remove rule from current position and insert at new position, along with its metadata
+	rule, meta := f.rules[from], f.metaAt(from)
+	f.removeAt(from)
+	f.insertAt(to, rule, meta)
+
+	return to, nil
 }
 
 // MoveRule relocates an existing rule to a new position relative to another rule in the IgnoreFile.
@@ -1053,6 +1537,8 @@ func (f *IgnoreFile) moveRule(from, to int) error {
 //   - If moving AFTER and the rule is already immediately after the target, no move occurs
 //   - If the calculated new position is the same as the current position, no move occurs
 //
+// Once moved, the rule joins the section of the target rule.
+//
 // Example:
 //
 //	// Move a file rule to appear before a directory rule
@@ -1099,14 +1585,21 @@ func (f *IgnoreFile) MoveRule(ruleToMove, targetRule Ruler, direction MoveDirect
 		return Result{}, nil
 	}
 
-	err := f.moveRule(moveIdx, newIdx)
+	position := f.managedPosition(moveIdx)
+	section := f.sectionAt(targetIdx)
+
+	movedIdx, err := f.moveRule(moveIdx, newIdx)
 	if err != nil {
 		return Result{}, err
 	}
 
+	// A rule always belongs to the section it is placed in
+	f.setSectionAt(movedIdx, section)
+
 	return Result{
-		Rule:   ruleToMove,
-		Result: MOVED,
-		Reason: reason,
+		Rule:     ruleToMove,
+		Result:   MOVED,
+		Reason:   reason,
+		Position: position,
 	}, nil
 }
@@ -1178,6 +1178,20 @@ func TestFixConflict(t *testing.T) {
 			},
 			errorMessage: "",
 		},
+		{
+			name: "Pass-ThreeCopiesKeepsOne",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					FileRule{path: ".env", act: INCLUDE},
+					FileRule{path: ".env", act: INCLUDE},
+					FileRule{path: ".env", act: INCLUDE},
+				},
+			},
+			result: []Ruler{
+				FileRule{path: ".env", act: INCLUDE},
+			},
+			errorMessage: "",
+		},
 	}
 
 	for _, tc := range tests {
@@ -1210,3 +1224,144 @@ func TestFixConflict(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsurePresent(t *testing.T) {
+	tests := []struct {
+		name         string
+		ignore       IgnoreFile
+		rule         Ruler
+		result       ActionResult
+		errorMessage string
+	}{
+		{
+			name:         "Pass-Added",
+			ignore:       IgnoreFile{rules: []Ruler{}},
+			rule:         ExtensionRule{ext: "log", act: INCLUDE},
+			result:       ADDED,
+			errorMessage: "",
+		},
+		{
+			name: "Pass-AlreadyPresent",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					ExtensionRule{ext: "log", act: INCLUDE},
+				},
+			},
+			rule:         ExtensionRule{ext: "log", act: INCLUDE},
+			result:       UNCHANGED,
+			errorMessage: "",
+		},
+		{
+			name: "Fail-Semantic",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					ExtensionRule{ext: "log", act: INCLUDE},
+				},
+			},
+			rule:         ExtensionRule{ext: "log", act: EXCLUDE},
+			errorMessage: semanticConflictError.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := tc.ignore.EnsurePresent(tc.rule)
+
+			checkErrors(tc.errorMessage, err, t)
+			if tc.errorMessage != "" {
+				return
+			}
+
+			if len(results) == 0 || results[0].Result != tc.result {
+				t.Errorf("expected first result to be %s, got %v", tc.result, results)
+			}
+
+			if len(tc.ignore.rules) != 1 {
+				t.Errorf("expected exactly 1 rule, found %d", len(tc.ignore.rules))
+			}
+		})
+	}
+}
+
+func TestEnsureAbsent(t *testing.T) {
+	tests := []struct {
+		name   string
+		ignore IgnoreFile
+		rule   Ruler
+		result ActionResult
+	}{
+		{
+			name: "Pass-Removed",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					FileRule{path: "todo.md", act: INCLUDE},
+				},
+			},
+			rule:   FileRule{path: "todo.md", act: INCLUDE},
+			result: REMOVED,
+		},
+		{
+			name: "Pass-RemovesEveryCopy",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					FileRule{path: "todo.md", act: INCLUDE},
+					ExtensionRule{ext: "log", act: INCLUDE},
+					FileRule{path: "todo.md", act: INCLUDE},
+				},
+			},
+			rule:   FileRule{path: "todo.md", act: INCLUDE},
+			result: REMOVED,
+		},
+		{
+			name:   "Pass-AlreadyAbsent",
+			ignore: IgnoreFile{rules: []Ruler{}},
+			rule:   FileRule{path: "todo.md", act: INCLUDE},
+			result: UNCHANGED,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tc.ignore.EnsureAbsent(tc.rule)
+			checkErrors("", err, t)
+
+			if result.Result != tc.result {
+				t.Errorf("expected result %s, got %s", tc.result, result.Result)
+			}
+
+			if ruleExists(tc.ignore, tc.rule) {
+				t.Errorf("expected to not find matching rule")
+			}
+		})
+	}
+}
+
+func TestEnsureAbsentUnmanaged(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "Fail-OnlyOutsideOfBlock",
+			content: "todo.md\n" + MANAGED_BLOCK_BEGIN + "\n*.log\n" + MANAGED_BLOCK_END + "\n",
+		},
+		{
+			name:    "Fail-InsideAndOutsideOfBlock",
+			content: MANAGED_BLOCK_BEGIN + "\ntodo.md\n*.log\n" + MANAGED_BLOCK_END + "\ntodo.md\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore := NewIgnoreFile()
+			checkErrors("", Parse(tc.content, &ignore), t)
+
+			_, err := ignore.EnsureAbsent(FileRule{path: "todo.md", act: INCLUDE})
+			checkErrors(ruleNotFoundError.Error(), err, t)
+
+			if rendered := Render(&ignore, RenderOptions{TrailingNewLine: true}); rendered != tc.content {
+				t.Errorf("expected the file to be left unchanged, got %q", rendered)
+			}
+		})
+	}
+}
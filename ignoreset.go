@@ -0,0 +1,206 @@
+package gignore
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// IgnoreFileSet holds every ignore file of a directory tree, such as the root .gitignore and
+// the .gitignore files of nested directories, and matches paths the way git does: rules in a
+// deeper file take precedence over rules in the files above it.
+type IgnoreFileSet struct {
+	name  string
+	files map[string]IgnoreFile // keyed by the directory the file applies to, "" for the root
+}
+
+// LoadIgnoreFileSet walks a directory tree and loads every ignore file with the given name.
+// Like git, files inside ignored directories and inside .git are not loaded.
+//
+// Parameters:
+//   - tree: The root of the directory tree, e.g. os.DirFS(".").
+//   - name: The name of the ignore files, e.g. ".gitignore".
+//
+// Returns the IgnoreFileSet and an error if the tree cannot be walked or a file cannot be parsed.
+//
+// Example:
+//
+//	set, err := LoadIgnoreFileSet(os.DirFS("."), ".gitignore")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	fmt.Println(set.Match("services/api/debug.log").Log())
+func LoadIgnoreFileSet(tree fs.FS, name string) (IgnoreFileSet, error) {
+	set := IgnoreFileSet{name: name, files: make(map[string]IgnoreFile)}
+
+	err := fs.WalkDir(tree, ".", func(dir string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() {
+			return nil
+		}
+
+		if dir == "." {
+			dir = ""
+		}
+
+		if entry.Name() == ".git" || (dir != "" && set.IsIgnored(dir+"/")) {
+			return fs.SkipDir
+		}
+
+		content, err := fs.ReadFile(tree, path.Join(dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fileReadError
+		}
+
+		ignoreFile := NewIgnoreFile()
+		if err := Parse(string(content), &ignoreFile); err != nil {
+			return err
+		}
+		ignoreFile.SetSource(path.Join(dir, name))
+
+		set.files[dir] = ignoreFile
+
+		return nil
+	})
+
+	return set, err
+}
+
+// Files returns the paths of every loaded ignore file, relative to the root of the tree.
+func (s IgnoreFileSet) Files() []string {
+	files := make([]string, 0, len(s.files))
+	for dir := range s.files {
+		files = append(files, path.Join(dir, s.name))
+	}
+
+	sort.Strings(files)
+
+	return files
+}
+
+// Match reports which rule, if any, decides whether a path is ignored. Paths are relative to
+// the root of the tree and a trailing "/" marks a directory, as in IgnoreFile.Match.
+//
+// The returned Match has its Source set to the ignore file the rule was loaded from.
+func (s IgnoreFileSet) Match(target string) Match {
+	match := Match{Path: target}
+
+	isDir := strings.HasSuffix(target, "/")
+	cleaned := cleanMatchPath(target)
+	if cleaned == "" {
+		return match
+	}
+
+	// A path inside an ignored directory can't be re-included, so the directory's rule decides
+	parts := strings.Split(cleaned, "/")
+	for i := 1; i <= len(parts); i++ {
+		last := i == len(parts)
+
+		decided := s.matchPath(strings.Join(parts[:i], "/"), isDir || !last)
+		if last || decided.Ignored() {
+			decided.Path = target
+			return decided
+		}
+	}
+
+	return match
+}
+
+// IsIgnored reports whether a path is ignored. See Match for how paths are interpreted.
+func (s IgnoreFileSet) IsIgnored(target string) bool {
+	return s.Match(target).Ignored()
+}
+
+// Checks the files that apply to a path from the deepest to the root, the first file with a matching rule decides
+func (s IgnoreFileSet) matchPath(target string, isDir bool) Match {
+	dir := path.Dir(target)
+	for {
+		if dir == "." {
+			dir = ""
+		}
+
+		if ignoreFile, ok := s.files[dir]; ok {
+			rel := strings.TrimPrefix(target, dir+"/")
+			rules := ignoreFile.allRules()
+
+			if idx := lastMatchingRule(rules, rel, isDir); idx != -1 {
+				return Match{
+					Source: path.Join(dir, s.name),
+					Rule:   rules[idx],
+					Line:   ignoreFile.allLines()[idx],
+				}
+			}
+		}
+
+		if dir == "" {
+			return Match{}
+		}
+
+		dir = path.Dir(dir)
+	}
+}
+
+// MARK: Tracked files
+
+// TrackedIgnored reports the tracked paths that are ignored. Git keeps tracking files that
+// were committed before a rule ignoring them was added, so these paths are still part of
+// the repository even though the ignore files say otherwise.
+//
+// Parameters:
+//   - tracked: Tracked paths relative to the root of the tree, e.g. from ReadIndex.
+//
+// Returns a Match for every tracked path that is ignored, naming the rule that ignores it.
+func (s IgnoreFileSet) TrackedIgnored(tracked []string) []Match {
+	matches := make([]Match, 0)
+
+	for _, trackedPath := range tracked {
+		if match := s.Match(trackedPath); match.Ignored() {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches
+}
+
+// FindTrackedIgnored reads the index and every .gitignore file of a git repository, and
+// reports the tracked files that are ignored along with the rule that ignores each of them.
+// The git binary is not needed.
+//
+// Parameters:
+//   - repo: The root directory of a git repository, e.g. os.DirFS(".").
+//
+// Returns a Match for every tracked path that is ignored and an error if the index or an
+// ignore file cannot be read.
+//
+// Example:
+//
+//	matches, err := FindTrackedIgnored(os.DirFS("."))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, match := range matches {
+//	    fmt.Println(match.Log()) // .gitignore:3:*.log	debug.log
+//	}
+func FindTrackedIgnored(repo fs.FS) ([]Match, error) {
+	tracked, err := LoadIndex(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := LoadIgnoreFileSet(repo, ".gitignore")
+	if err != nil {
+		return nil, err
+	}
+
+	return set.TrackedIgnored(tracked), nil
+}
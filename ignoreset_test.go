@@ -0,0 +1,80 @@
+package gignore
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func testRepository(t *testing.T) fstest.MapFS {
+	t.Helper()
+
+	index, err := os.ReadFile("testdata/index-v2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	return fstest.MapFS{
+		".git/index":          {Data: index},
+		".gitignore":          {Data: []byte("*.log\nbuild/\n")},
+		"docs/.gitignore":     {Data: []byte("guide/\n!*.log\n")},
+		"docs/debug.log":      {},
+		"docs/guide/intro.md": {},
+		"build/.gitignore":    {Data: []byte("!x.c\n")},
+		"build/x.c":           {},
+		"a.log":               {},
+		"main.go":             {},
+	}
+}
+
+func TestIgnoreFileSetMatch(t *testing.T) {
+	set, err := LoadIgnoreFileSet(testRepository(t), ".gitignore")
+	checkErrors("", err, t)
+
+	// build/.gitignore is inside an ignored directory, so git never reads it
+	if files := set.Files(); !reflect.DeepEqual(files, []string{".gitignore", "docs/.gitignore"}) {
+		t.Errorf("expected root and docs ignore files, got %v", files)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "Pass-Root", path: "a.log", expected: ".gitignore:1:*.log\ta.log"},
+		{name: "Pass-NestedOverridesRoot", path: "docs/debug.log", expected: "docs/.gitignore:2:!*.log\tdocs/debug.log"},
+		{name: "Pass-NestedDirectory", path: "docs/guide/intro.md", expected: "docs/.gitignore:1:guide/\tdocs/guide/intro.md"},
+		{name: "Pass-IgnoredParent", path: "build/x.c", expected: ".gitignore:2:build/\tbuild/x.c"},
+		{name: "Pass-NoMatch", path: "main.go", expected: "::\tmain.go"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if log := set.Match(tc.path).Log(); log != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, log)
+			}
+		})
+	}
+}
+
+func TestFindTrackedIgnored(t *testing.T) {
+	matches, err := FindTrackedIgnored(testRepository(t))
+	checkErrors("", err, t)
+
+	expected := []string{
+		".gitignore:1:*.log\ta.log",
+		".gitignore:2:build/\tbuild/x.c",
+		"docs/.gitignore:1:guide/\tdocs/guide/intro.md",
+	}
+
+	if len(matches) != len(expected) {
+		t.Fatalf("expected %d matches, got %d: %v", len(expected), len(matches), matches)
+	}
+
+	for idx, match := range matches {
+		if match.Log() != expected[idx] {
+			t.Errorf("expected %q, got %q", expected[idx], match.Log())
+		}
+	}
+}
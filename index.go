@@ -0,0 +1,200 @@
+package gignore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+)
+
+var (
+	invalidIndexSignatureError   = errors.New("invalid git index signature")
+	unsupportedIndexVersionError = errors.New("unsupported git index version, only versions 2 to 4 are supported")
+	truncatedIndexError          = errors.New("git index is truncated")
+	invalidIndexEntryError       = errors.New("git index contains an invalid entry")
+)
+
+// MARK: Index format
+const (
+	indexSignature = "DIRC"
+	indexHashSize  = 20 // SHA-1 object names
+
+	// ctime, mtime, dev, ino, mode, uid, gid and size (4 bytes each, times are 8), the object name and flags
+	indexEntryHeaderSize = 40 + indexHashSize + 2
+
+	indexFlagExtended = 0x4000
+)
+
+// ReadIndex reads a git index (the .git/index file) and returns the tracked paths in index
+// order. Versions 2, 3 and 4 of the index format are supported, for repositories using SHA-1
+// object names. Paths are relative to the root of the repository and use "/" as separator.
+// Paths with merge conflicts are listed once.
+//
+// Parameters:
+//   - reader: Any io.Reader containing a git index.
+//
+// Returns the tracked paths and an error if the index is malformed or uses an unsupported version.
+//
+// Example:
+//
+//	file, err := os.Open(".git/index")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer file.Close()
+//
+//	tracked, err := ReadIndex(file)
+func ReadIndex(reader io.Reader) ([]string, error) {
+	r := bufio.NewReader(reader)
+
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, truncatedIndexError
+	}
+
+	if string(header[:4]) != indexSignature {
+		return nil, invalidIndexSignatureError
+	}
+
+	version := binary.BigEndian.Uint32(header[4:8])
+	if version < 2 || version > 4 {
+		return nil, unsupportedIndexVersionError
+	}
+
+	count := binary.BigEndian.Uint32(header[8:12])
+	paths := make([]string, 0, count)
+
+	previous := ""
+	entry := make([]byte, indexEntryHeaderSize)
+	for range count {
+		if _, err := io.ReadFull(r, entry); err != nil {
+			return nil, truncatedIndexError
+		}
+
+		flags := binary.BigEndian.Uint16(entry[indexEntryHeaderSize-2:])
+		size := indexEntryHeaderSize
+
+		if flags&indexFlagExtended != 0 {
+			if version < 3 {
+				return nil, invalidIndexEntryError
+			}
+
+			if _, err := r.Discard(2); err != nil {
+				return nil, truncatedIndexError
+			}
+			size += 2
+		}
+
+		var path string
+		var err error
+		if version == 4 {
+			path, err = readCompressedIndexPath(r, previous)
+		} else {
+			path, err = readPaddedIndexPath(r, size)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Conflicted paths have one entry per stage, always next to each other
+		if len(paths) == 0 || paths[len(paths)-1] != path {
+			paths = append(paths, path)
+		}
+
+		previous = path
+	}
+
+	return paths, nil
+}
+
+// Reads a NUL terminated path followed by the NUL padding that aligns entries to 8 bytes
+func readPaddedIndexPath(r *bufio.Reader, headerSize int) (string, error) {
+	raw, err := r.ReadBytes(0)
+	if err != nil {
+		return "", truncatedIndexError
+	}
+
+	path := string(raw[:len(raw)-1])
+	if path == "" {
+		return "", invalidIndexEntryError
+	}
+
+	// The entry, including at least one NUL, is padded to a multiple of 8 bytes
+	consumed := headerSize + len(raw)
+	if padding := (8 - consumed%8) % 8; padding > 0 {
+		if _, err := r.Discard(padding); err != nil {
+			return "", truncatedIndexError
+		}
+	}
+
+	return path, nil
+}
+
+// Reads a path stored as the number of bytes to drop from the previous path and the suffix to append
+func readCompressedIndexPath(r *bufio.Reader, previous string) (string, error) {
+	strip, err := readIndexVarint(r)
+	if err != nil {
+		return "", err
+	}
+
+	if strip > uint64(len(previous)) {
+		return "", invalidIndexEntryError
+	}
+
+	suffix, err := r.ReadBytes(0)
+	if err != nil {
+		return "", truncatedIndexError
+	}
+
+	path := previous[:len(previous)-int(strip)] + string(bytes.TrimSuffix(suffix, []byte{0}))
+	if path == "" {
+		return "", invalidIndexEntryError
+	}
+
+	return path, nil
+}
+
+// Decodes git's offset varint, where each continuation also adds one to avoid redundant encodings
+func readIndexVarint(r *bufio.Reader) (uint64, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return 0, truncatedIndexError
+	}
+
+	value := uint64(c & 0x7f)
+	for c&0x80 != 0 {
+		if c, err = r.ReadByte(); err != nil {
+			return 0, truncatedIndexError
+		}
+
+		value = ((value + 1) << 7) | uint64(c&0x7f)
+	}
+
+	return value, nil
+}
+
+// LoadIndex reads the tracked paths from the .git/index file of a repository. See ReadIndex.
+//
+// Parameters:
+//   - repo: The root directory of a git repository, e.g. os.DirFS(".").
+//
+// Returns the tracked paths and an error if the index cannot be opened or read. A repository
+// without an index, such as one created by "git init" before anything was added, has no
+// tracked paths.
+func LoadIndex(repo fs.FS) ([]string, error) {
+	file, err := repo.Open(".git/index")
+	if errors.Is(err, fs.ErrNotExist) {
+		if _, statErr := fs.Stat(repo, ".git"); statErr == nil {
+			return []string{}, nil
+		}
+	}
+	if err != nil {
+		return nil, fileOpenError
+	}
+
+	defer file.Close()
+
+	return ReadIndex(file)
+}
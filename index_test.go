@@ -0,0 +1,94 @@
+package gignore
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadIndex(t *testing.T) {
+	expected := []string{".gitignore", "a.log", "build/x.c", "docs/guide/intro.md", "main.go"}
+
+	tests := []struct {
+		name     string
+		fixture  string
+		expected []string
+	}{
+		{name: "Pass-Version2", fixture: "testdata/index-v2", expected: expected},
+		{name: "Pass-Version3", fixture: "testdata/index-v3", expected: append(expected, "new.go")},
+		{name: "Pass-Version4", fixture: "testdata/index-v4", expected: append(expected, "new.go")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			file, err := os.Open(tc.fixture)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			defer file.Close()
+
+			paths, err := ReadIndex(file)
+			checkErrors("", err, t)
+
+			if !reflect.DeepEqual(paths, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, paths)
+			}
+		})
+	}
+}
+
+func TestReadIndexErrors(t *testing.T) {
+	valid, err := os.ReadFile("testdata/index-v2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	unsupported := bytes.Clone(valid)
+	unsupported[7] = 5
+
+	tests := []struct {
+		name     string
+		content  []byte
+		expected error
+	}{
+		{name: "Fail-Signature", content: []byte("NOPE\x00\x00\x00\x02\x00\x00\x00\x00"), expected: invalidIndexSignatureError},
+		{name: "Fail-Version", content: unsupported, expected: unsupportedIndexVersionError},
+		{name: "Fail-Truncated", content: valid[:100], expected: truncatedIndexError},
+		{name: "Fail-Empty", content: []byte{}, expected: truncatedIndexError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ReadIndex(bytes.NewReader(tc.content)); !errors.Is(err, tc.expected) {
+				t.Errorf("expected error %v, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestLoadIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     fstest.MapFS
+		expected error
+	}{
+		{name: "Pass-NoIndexYet", repo: fstest.MapFS{".git/HEAD": {Data: []byte("ref: refs/heads/main\n")}}},
+		{name: "Fail-NotARepository", repo: fstest.MapFS{"main.go": {}}, expected: fileOpenError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tracked, err := LoadIndex(tc.repo)
+			if !errors.Is(err, tc.expected) {
+				t.Fatalf("expected error %v, got %v", tc.expected, err)
+			}
+
+			if err == nil && len(tracked) != 0 {
+				t.Errorf("expected no tracked paths, got %v", tracked)
+			}
+		})
+	}
+}
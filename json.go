@@ -0,0 +1,285 @@
+package gignore
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// JSON encoding gives Result, Conflict and IgnoreFile a stable schema for tools that consume
+// gignore's output. Rules are encoded as objects:
+//
+//	{
+//	  "kind": "dir",                        // file, ext, dir or glob
+//	  "pattern": "build",                   // what the rule's constructor takes
+//	  "action": "include",                  // include or exclude
+//	  "mode": "recursive",                  // directory rules only
+//	  "rendered": "build/**",               // the line in the ignore file
+//	  "position": {"source": ".gitignore", "line": 3} // parsed rules only
+//	}
+//
+// The pattern is the path of a file rule, "*.ext" for an extension rule, the name of a directory
+// rule and the pattern of a glob rule, so kind, pattern, mode and action are enough to recreate
+// the rule with NewFileRule, NewExtensionRule, NewDirectoryRule or NewGlobRule.
+
+var (
+	unknownRuleKindError  = errors.New("unknown rule kind")
+	missingRuleError      = errors.New("missing rule")
+	ruleKindMismatchError = errors.New("rule does not match its kind")
+)
+
+// MARK: Rules
+
+// Rule kinds, named like the arguments of the add and rm commands
+const (
+	FILE_KIND      = "file"
+	EXTENSION_KIND = "ext"
+	DIRECTORY_KIND = "dir"
+	GLOB_KIND      = "glob"
+)
+
+type ruleJSON struct {
+	Kind     string        `json:"kind"`
+	Pattern  string        `json:"pattern"`
+	Action   Action        `json:"action"`
+	Mode     DirectoryMode `json:"mode,omitempty"`
+	Rendered string        `json:"rendered"`
+	Position *Position     `json:"position,omitempty"`
+}
+
+// RuleKind returns the kind of a rule: "file", "ext", "dir" or "glob". Returns an empty string
+// for rules of other types.
+func RuleKind(rule Ruler) string {
+	switch rule.(type) {
+	case FileRule:
+		return FILE_KIND
+	case ExtensionRule:
+		return EXTENSION_KIND
+	case DirectoryRule:
+		return DIRECTORY_KIND
+	case GlobRule:
+		return GLOB_KIND
+	default:
+		return ""
+	}
+}
+
+func newRuleJSON(rule Ruler, position Position) ruleJSON {
+	encoded := ruleJSON{
+		Kind:     RuleKind(rule),
+		Pattern:  rule.Pattern(),
+		Action:   rule.Action(),
+		Rendered: rule.Render(),
+	}
+
+	if dir, ok := rule.(DirectoryRule); ok {
+		encoded.Pattern = dir.name
+		encoded.Mode = dir.mode
+	}
+
+	if position.IsValid() {
+		encoded.Position = &position
+	}
+
+	return encoded
+}
+
+// Creates a rule from its kind, pattern, mode and action with the validating constructors
+func newRuleOfKind(kind, pattern string, mode DirectoryMode, action Action) (Ruler, error) {
+	switch kind {
+	case FILE_KIND:
+		return NewFileRule(pattern, action)
+	case EXTENSION_KIND:
+		return NewExtensionRule(pattern, action)
+	case DIRECTORY_KIND:
+		return NewDirectoryRule(pattern, mode, action)
+	case GLOB_KIND:
+		return NewGlobRule(pattern, action)
+	default:
+		return nil, unknownRuleKindError
+	}
+}
+
+// Returns the rule and its position, checking the rendered form matches if it is given
+func (r ruleJSON) rule() (Ruler, Position, error) {
+	rule, err := newRuleOfKind(r.Kind, r.Pattern, r.Mode, r.Action)
+	if err != nil {
+		return nil, Position{}, err
+	}
+
+	if r.Rendered != "" && r.Rendered != rule.Render() {
+		return nil, Position{}, ruleKindMismatchError
+	}
+
+	var position Position
+	if r.Position != nil {
+		position = *r.Position
+	}
+
+	return rule, position, nil
+}
+
+// MARK: Results
+
+type resultJSON struct {
+	Rule   *ruleJSON    `json:"rule"`
+	Result ActionResult `json:"result"`
+	Reason ActionReason `json:"reason"`
+}
+
+// MarshalJSON encodes the Result as {"rule": {...}, "result": "REMOVED", "reason": "MINIMIZED"},
+// with the Result's Position as the position of the rule. An empty Result, which operations
+// return when nothing happened, is encoded as null.
+func (r Result) MarshalJSON() ([]byte, error) {
+	if r.Rule == nil && r.Result == 0 && r.Reason == 0 {
+		return []byte("null"), nil
+	}
+
+	if r.Rule == nil {
+		return nil, missingRuleError
+	}
+
+	rule := newRuleJSON(r.Rule, r.Position)
+
+	return json.Marshal(resultJSON{Rule: &rule, Result: r.Result, Reason: r.Reason})
+}
+
+// UnmarshalJSON decodes a Result encoded by MarshalJSON. The rule is recreated with its
+// validating constructor, so invalid rules are reported as errors.
+func (r *Result) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var decoded resultJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	if decoded.Rule == nil {
+		return missingRuleError
+	}
+
+	rule, position, err := decoded.Rule.rule()
+	if err != nil {
+		return err
+	}
+
+	*r = Result{Rule: rule, Result: decoded.Result, Reason: decoded.Reason, Position: position}
+
+	return nil
+}
+
+// MARK: Conflicts
+
+type conflictJSON struct {
+	Type  string    `json:"type"`
+	Left  *ruleJSON `json:"left"`
+	Right *ruleJSON `json:"right"`
+}
+
+// MarshalJSON encodes the Conflict as {"type": "UNREACHABLE_RULE", "left": {...}, "right": {...}},
+// with LeftPosition and RightPosition as the positions of the rules. REDUNDANT_RULE conflicts
+// are encoded with the type "REDUNDANT_RULE".
+func (c Conflict) MarshalJSON() ([]byte, error) {
+	if c.Left == nil || c.Right == nil {
+		return nil, missingRuleError
+	}
+
+	left := newRuleJSON(c.Left, c.LeftPosition)
+	right := newRuleJSON(c.Right, c.RightPosition)
+
+	return json.Marshal(conflictJSON{Type: conflictTypeName(c.ConflictType), Left: &left, Right: &right})
+}
+
+// UnmarshalJSON decodes a Conflict encoded by MarshalJSON. The rules are recreated with their
+// validating constructors, so invalid rules are reported as errors, as are unknown conflict
+// types. Both "REDUNDANT_RULE" and "REDUNANT_RULE" decode to REDUNDANT_RULE.
+func (c *Conflict) UnmarshalJSON(data []byte) error {
+	var decoded conflictJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	if decoded.Left == nil || decoded.Right == nil {
+		return missingRuleError
+	}
+
+	conflictType, err := conflictTypeFromName(decoded.Type)
+	if err != nil {
+		return err
+	}
+
+	left, leftPosition, err := decoded.Left.rule()
+	if err != nil {
+		return err
+	}
+
+	right, rightPosition, err := decoded.Right.rule()
+	if err != nil {
+		return err
+	}
+
+	*c = Conflict{
+		Left:          left,
+		Right:         right,
+		ConflictType:  conflictType,
+		LeftPosition:  leftPosition,
+		RightPosition: rightPosition,
+	}
+
+	return nil
+}
+
+// MARK: Ignore files
+
+type ignoreFileRuleJSON struct {
+	ruleJSON
+	Section   string   `json:"section,omitempty"`
+	Comments  []string `json:"comments,omitempty"`
+	Unmanaged bool     `json:"unmanaged,omitempty"`
+}
+
+type ignoreFileJSON struct {
+	Source string               `json:"source,omitempty"`
+	Rules  []ignoreFileRuleJSON `json:"rules"`
+}
+
+// Strips the "#" and the space after it, leaving the text of a comment line
+func commentText(comment string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(comment, "#"), " ")
+}
+
+// MarshalJSON encodes the IgnoreFile as {"source": ".gitignore", "rules": [...]}. Every rule
+// is listed in file order, including rules outside of a managed block, which are marked with
+// "unmanaged": true even if the block has a copy of the same rule. Besides the fields every rule
+// has, rules carry the section they belong to and the text of the comments directly above them,
+// without the leading "# ".
+//
+// Example output:
+//
+//	{"source":".gitignore","rules":[{"kind":"ext","pattern":"*.log","action":"include",
+//	"rendered":"*.log","position":{"source":".gitignore","line":2},"section":"Logs"}]}
+func (f IgnoreFile) MarshalJSON() ([]byte, error) {
+	encoded := ignoreFileJSON{Source: f.source, Rules: make([]ignoreFileRuleJSON, 0, len(f.rules))}
+
+	metas := f.allMeta()
+	for idx, rule := range f.allRules() {
+		meta := metas[idx]
+		managed := f.managedAt(idx)
+
+		var comments []string
+		for _, comment := range meta.comments {
+			comments = append(comments, commentText(comment))
+		}
+
+		encoded.Rules = append(encoded.Rules, ignoreFileRuleJSON{
+			ruleJSON:  newRuleJSON(rule, Position{Source: f.source, Line: meta.line}),
+			Section:   meta.section,
+			Comments:  comments,
+			Unmanaged: !managed,
+		})
+	}
+
+	return json.Marshal(encoded)
+}
@@ -0,0 +1,251 @@
+package gignore
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTextMarshaling(t *testing.T) {
+	type textValue interface {
+		encoding.TextMarshaler
+	}
+
+	tests := []struct {
+		name     string
+		value    textValue
+		decoded  encoding.TextUnmarshaler
+		expected string
+	}{
+		{name: "Pass-Include", value: INCLUDE, decoded: new(Action), expected: "include"},
+		{name: "Pass-Exclude", value: EXCLUDE, decoded: new(Action), expected: "exclude"},
+		{name: "Pass-Directory", value: DIRECTORY, decoded: new(DirectoryMode), expected: "directory"},
+		{name: "Pass-Children", value: CHILDREN, decoded: new(DirectoryMode), expected: "children"},
+		{name: "Pass-Recursive", value: RECURSIVE, decoded: new(DirectoryMode), expected: "recursive"},
+		{name: "Pass-Anywhere", value: ANYWHERE, decoded: new(DirectoryMode), expected: "anywhere"},
+		{name: "Pass-RootOnly", value: ROOT_ONLY, decoded: new(DirectoryMode), expected: "root_only"},
+		{name: "Pass-Before", value: BEFORE, decoded: new(MoveDirection), expected: "before"},
+		{name: "Pass-After", value: AFTER, decoded: new(MoveDirection), expected: "after"},
+		{name: "Pass-ReviewRecommended", value: REVIEW_RECOMMENDED, decoded: new(ActionResult), expected: "REVIEW_RECOMMENDED"},
+		{name: "Pass-Unchanged", value: UNCHANGED, decoded: new(ActionResult), expected: "UNCHANGED"},
+		{name: "Pass-Requested", value: REQUESTED, decoded: new(ActionReason), expected: "REQUESTED"},
+		{name: "Pass-Minimized", value: MINIMIZED, decoded: new(ActionReason), expected: "MINIMIZED"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			text, err := tc.value.MarshalText()
+			checkErrors("", err, t)
+
+			if string(text) != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, text)
+			}
+
+			checkErrors("", tc.decoded.UnmarshalText(text), t)
+
+			if again, _ := tc.decoded.(textValue).MarshalText(); string(again) != tc.expected {
+				t.Errorf("expected %q to round-trip, got %q", tc.expected, again)
+			}
+		})
+	}
+}
+
+func TestTextMarshalingInvalid(t *testing.T) {
+	if _, err := Action(0).MarshalText(); !errors.Is(err, invalidActionError) {
+		t.Errorf("expected invalid action error, got %v", err)
+	}
+
+	if _, err := ActionResult(0).MarshalText(); !errors.Is(err, invalidActionResultError) {
+		t.Errorf("expected invalid action result error, got %v", err)
+	}
+
+	var mode DirectoryMode
+	if err := mode.UnmarshalText([]byte("sideways")); !errors.Is(err, invalidDirectoryModeError) {
+		t.Errorf("expected invalid directory mode error, got %v", err)
+	}
+
+	var direction MoveDirection
+	if err := direction.UnmarshalText([]byte("under")); !errors.Is(err, invalidateDirectionError) {
+		t.Errorf("expected invalid direction error, got %v", err)
+	}
+
+	var reason ActionReason
+	if err := reason.UnmarshalText([]byte("requested")); !errors.Is(err, invalidActionReasonError) {
+		t.Errorf("expected invalid action reason error, got %v", err)
+	}
+}
+
+func TestResultJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   Result
+		expected string
+	}{
+		{
+			name:     "Pass-Extension",
+			result:   Result{Rule: ExtensionRule{ext: "log", act: INCLUDE}, Result: ADDED, Reason: REQUESTED},
+			expected: `{"rule":{"kind":"ext","pattern":"*.log","action":"include","rendered":"*.log"},"result":"ADDED","reason":"REQUESTED"}`,
+		},
+		{
+			name: "Pass-DirectoryWithPosition",
+			result: Result{
+				Rule:     DirectoryRule{name: "build", mode: RECURSIVE, act: EXCLUDE},
+				Result:   REMOVED,
+				Reason:   MINIMIZED,
+				Position: Position{Source: ".gitignore", Line: 3},
+			},
+			expected: `{"rule":{"kind":"dir","pattern":"build","action":"exclude","mode":"recursive","rendered":"!build/**","position":{"source":".gitignore","line":3}},"result":"REMOVED","reason":"MINIMIZED"}`,
+		},
+		{
+			name:     "Pass-Empty",
+			result:   Result{},
+			expected: `null`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := json.Marshal(tc.result)
+			checkErrors("", err, t)
+
+			if string(encoded) != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, encoded)
+			}
+
+			var decoded Result
+			checkErrors("", json.Unmarshal(encoded, &decoded), t)
+
+			if decoded != tc.result {
+				t.Errorf("expected %+v to round-trip, got %+v", tc.result, decoded)
+			}
+		})
+	}
+}
+
+func TestResultJSONInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "Fail-UnknownKind", data: `{"rule":{"kind":"regex","pattern":"x","action":"include"},"result":"ADDED","reason":"REQUESTED"}`},
+		{name: "Fail-EmptyPattern", data: `{"rule":{"kind":"file","pattern":" ","action":"include"},"result":"ADDED","reason":"REQUESTED"}`},
+		{name: "Fail-RenderedMismatch", data: `{"rule":{"kind":"file","pattern":"a.txt","action":"include","rendered":"b.txt"},"result":"ADDED","reason":"REQUESTED"}`},
+		{name: "Fail-MissingRule", data: `{"result":"ADDED","reason":"REQUESTED"}`},
+		{name: "Fail-UnknownResult", data: `{"rule":{"kind":"file","pattern":"a.txt","action":"include"},"result":"DELETED","reason":"REQUESTED"}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var decoded Result
+			if err := json.Unmarshal([]byte(tc.data), &decoded); err == nil {
+				t.Errorf("expected an error, got %+v", decoded)
+			}
+		})
+	}
+}
+
+func TestConflictJSON(t *testing.T) {
+	ignoreFile := NewIgnoreFile()
+	checkErrors("", Parse("build/**\n*.log\nbuild/\n", &ignoreFile), t)
+	ignoreFile.SetSource(".gitignore")
+
+	conflicts := ignoreFile.FindConflicts()
+	encoded, err := json.Marshal(conflicts)
+	checkErrors("", err, t)
+
+	expected := `[{"type":"UNREACHABLE_RULE",` +
+		`"left":{"kind":"dir","pattern":"build","action":"include","mode":"recursive","rendered":"build/**","position":{"source":".gitignore","line":1}},` +
+		`"right":{"kind":"dir","pattern":"build","action":"include","mode":"directory","rendered":"build/","position":{"source":".gitignore","line":3}}}]`
+	if string(encoded) != expected {
+		t.Errorf("expected %s, got %s", expected, encoded)
+	}
+
+	var decoded []Conflict
+	checkErrors("", json.Unmarshal(encoded, &decoded), t)
+
+	if len(decoded) != 1 || decoded[0] != conflicts[0] {
+		t.Errorf("expected %+v to round-trip, got %+v", conflicts, decoded)
+	}
+}
+
+func TestConflictTypeJSON(t *testing.T) {
+	rule := `{"kind":"ext","pattern":"*.log","action":"include"}`
+
+	tests := []struct {
+		name         string
+		conflictType string
+		expected     ConflictType
+		errorMessage string
+	}{
+		{name: "Pass-Redundant", conflictType: "REDUNDANT_RULE", expected: REDUNDANT_RULE},
+		{name: "Pass-RedundantAsLogged", conflictType: "REDUNANT_RULE", expected: REDUNDANT_RULE},
+		{name: "Pass-Semantic", conflictType: "SEMANTIC_CONFLICT", expected: SEMANTIC_CONFLICT},
+		{name: "Fail-Unknown", conflictType: "DUPLICATE_RULE", errorMessage: unknownConflictTypeError.Error()},
+		{name: "Fail-Missing", conflictType: "", errorMessage: unknownConflictTypeError.Error()},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data := fmt.Sprintf(`{"type":%q,"left":%s,"right":%s}`, tc.conflictType, rule, rule)
+
+			var decoded Conflict
+			err := json.Unmarshal([]byte(data), &decoded)
+			checkErrors(tc.errorMessage, err, t)
+
+			if tc.errorMessage == "" && decoded.ConflictType != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, decoded.ConflictType)
+			}
+		})
+	}
+
+	encoded, err := json.Marshal(Conflict{Left: ExtensionRule{ext: "log", act: INCLUDE}, Right: ExtensionRule{ext: "log", act: INCLUDE}, ConflictType: REDUNDANT_RULE})
+	checkErrors("", err, t)
+
+	if !strings.HasPrefix(string(encoded), `{"type":"REDUNDANT_RULE",`) {
+		t.Errorf("expected the type to be spelled REDUNDANT_RULE, got %s", encoded)
+	}
+}
+
+func TestIgnoreFileJSON(t *testing.T) {
+	ignoreFile := NewIgnoreFile()
+	checkErrors("", Parse("custom.txt\n# BEGIN gignore:managed\n# Logs\n# Rotated logs too\n*.log\n# END gignore:managed\n", &ignoreFile), t)
+	ignoreFile.SetSource(".gitignore")
+
+	encoded, err := json.Marshal(ignoreFile)
+	checkErrors("", err, t)
+
+	expected := `{"source":".gitignore","rules":[` +
+		`{"kind":"file","pattern":"custom.txt","action":"include","rendered":"custom.txt","position":{"source":".gitignore","line":1},"unmanaged":true},` +
+		`{"kind":"ext","pattern":"*.log","action":"include","rendered":"*.log","position":{"source":".gitignore","line":5},"section":"Logs","comments":["Rotated logs too"]}]}`
+	if string(encoded) != expected {
+		t.Errorf("expected %s, got %s", expected, encoded)
+	}
+}
+
+func TestIgnoreFileJSONRepeatedRule(t *testing.T) {
+	ignoreFile := NewIgnoreFile()
+	checkErrors("", Parse("*.log\n# BEGIN gignore:managed\n# Logs\n*.log\n# END gignore:managed\n*.log\n", &ignoreFile), t)
+
+	encoded, err := json.Marshal(ignoreFile)
+	checkErrors("", err, t)
+
+	var decoded ignoreFileJSON
+	checkErrors("", json.Unmarshal(encoded, &decoded), t)
+
+	if len(decoded.Rules) != 3 {
+		t.Fatalf("expected 3 rules, got %s", encoded)
+	}
+
+	for idx, expected := range []bool{true, false, true} {
+		if rule := decoded.Rules[idx]; rule.Unmanaged != expected {
+			t.Errorf("expected rule %d to have unmanaged %t, got %s", idx, expected, encoded)
+		}
+	}
+
+	if section := decoded.Rules[1].Section; section != "Logs" {
+		t.Errorf("expected the managed copy to be in section Logs, got %q", section)
+	}
+}
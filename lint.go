@@ -0,0 +1,342 @@
+package gignore
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var unknownLintCheckError = errors.New("unknown lint check")
+
+// MARK: Severity
+type Severity string
+
+const (
+	ERROR   Severity = "ERROR"   // The rule does not do what it looks like it does
+	WARNING Severity = "WARNING" // The rule works, but likely not as intended
+	INFO    Severity = "INFO"    // Style or portability notes
+)
+
+// MARK: Checks
+type LintCheckID string
+
+const (
+	TRAILING_WHITESPACE          LintCheckID = "TRAILING_WHITESPACE"          // Unescaped trailing spaces are stripped by git
+	BACKSLASH_PATH               LintCheckID = "BACKSLASH_PATH"               // Windows style separators never match
+	MID_SEGMENT_DOUBLE_STAR      LintCheckID = "MID_SEGMENT_DOUBLE_STAR"      // "a**b" behaves like "a*b"
+	UNTERMINATED_CHARACTER_CLASS LintCheckID = "UNTERMINATED_CHARACTER_CLASS" // "[" without "]" never matches
+	ABSOLUTE_PATH                LintCheckID = "ABSOLUTE_PATH"                // Patterns are relative to the ignore file
+	DOT_SLASH_PREFIX             LintCheckID = "DOT_SLASH_PREFIX"             // "./build" and "src/./gen" never match
+	INVALID_UTF8                 LintCheckID = "INVALID_UTF8"                 // Matches raw bytes, likely a broken encoding
+)
+
+// LintCheck inspects a single rule line of an ignore file. Check receives the line exactly as
+// it appears in the file and returns a message describing the problem, if any.
+type LintCheck struct {
+	ID       LintCheckID
+	Severity Severity
+	Check    func(line string) (string, bool)
+}
+
+// Diagnostic is a problem found by a LintCheck.
+type Diagnostic struct {
+	Check    LintCheckID
+	Severity Severity
+	Line     int
+	Message  string
+}
+
+// Log returns a formatted string representation of the Diagnostic suitable for logging
+// or display purposes.
+//
+// Example output: "3: WARNING TRAILING_WHITESPACE: trailing spaces are stripped by git"
+func (d Diagnostic) Log() string {
+	return fmt.Sprintf("%d: %s %s: %s", d.Line, d.Severity, d.Check, d.Message)
+}
+
+// DefaultLintChecks returns the checks built into gignore.
+func DefaultLintChecks() []LintCheck {
+	return []LintCheck{
+		{ID: TRAILING_WHITESPACE, Severity: WARNING, Check: checkTrailingWhitespace},
+		{ID: BACKSLASH_PATH, Severity: WARNING, Check: checkBackslashPath},
+		{ID: MID_SEGMENT_DOUBLE_STAR, Severity: WARNING, Check: checkMidSegmentDoubleStar},
+		{ID: UNTERMINATED_CHARACTER_CLASS, Severity: ERROR, Check: checkUnterminatedCharacterClass},
+		{ID: ABSOLUTE_PATH, Severity: ERROR, Check: checkAbsolutePath},
+		{ID: DOT_SLASH_PREFIX, Severity: ERROR, Check: checkDotSlashPrefix},
+		{ID: INVALID_UTF8, Severity: WARNING, Check: checkInvalidUTF8},
+	}
+}
+
+// MARK: Linter
+type Linter struct {
+	checks   []LintCheck
+	disabled map[LintCheckID]bool
+}
+
+// NewLinter creates a Linter that runs the given checks, or DefaultLintChecks if none are given.
+//
+// Example:
+//
+//	linter := NewLinter()
+//	linter.Disable(TRAILING_WHITESPACE)
+//
+//	for _, diagnostic := range linter.Lint(content) {
+//	    fmt.Println(diagnostic.Log())
+//	}
+func NewLinter(checks ...LintCheck) Linter {
+	if len(checks) == 0 {
+		checks = DefaultLintChecks()
+	}
+
+	linter := Linter{disabled: make(map[LintCheckID]bool)}
+	for _, check := range checks {
+		linter.Register(check)
+	}
+
+	return linter
+}
+
+// Register adds a check to the Linter, replacing any registered check with the same ID.
+func (l *Linter) Register(check LintCheck) {
+	for idx, existing := range l.checks {
+		if existing.ID == check.ID {
+			l.checks[idx] = check
+			return
+		}
+	}
+
+	l.checks = append(l.checks, check)
+}
+
+// Checks returns the IDs of every registered check, in registration order.
+func (l Linter) Checks() []LintCheckID {
+	ids := make([]LintCheckID, 0, len(l.checks))
+	for _, check := range l.checks {
+		ids = append(ids, check.ID)
+	}
+
+	return ids
+}
+
+// Enable turns a previously disabled check back on. Returns an error if no check with the ID is registered.
+func (l *Linter) Enable(id LintCheckID) error {
+	if !l.registered(id) {
+		return unknownLintCheckError
+	}
+
+	delete(l.disabled, id)
+
+	return nil
+}
+
+// Disable turns a check off. Returns an error if no check with the ID is registered.
+func (l *Linter) Disable(id LintCheckID) error {
+	if !l.registered(id) {
+		return unknownLintCheckError
+	}
+
+	if l.disabled == nil {
+		l.disabled = make(map[LintCheckID]bool)
+	}
+	l.disabled[id] = true
+
+	return nil
+}
+
+// Enabled reports whether a check is registered and enabled.
+func (l Linter) Enabled(id LintCheckID) bool {
+	return l.registered(id) && !l.disabled[id]
+}
+
+func (l Linter) registered(id LintCheckID) bool {
+	for _, check := range l.checks {
+		if check.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Lint runs every enabled check against each rule line of ignore file content. Comments and
+// blank lines are skipped. Lint works on the raw content rather than a parsed IgnoreFile,
+// because some problems (like trailing whitespace) are lost when the content is parsed. Like
+// Parse, Lint strips a leading byte order mark and accepts "\n", "\r\n" and "\r" line breaks.
+//
+// Findings are silenced by "# gignore:disable <CHECK>" anywhere in the content, and by
+// "# gignore:ignore-next-line <CHECK>" directly above the offending line.
+//
+// Parameters:
+//   - content: The string content of an ignore file.
+//
+// Returns the diagnostics in line order. An empty slice means no problems were found.
+//
+// Example:
+//
+//	linter := NewLinter()
+//	diagnostics := linter.Lint("*.log \n./build/\n")
+//	// 1: WARNING TRAILING_WHITESPACE: ...
+//	// 2: ERROR DOT_SLASH_PREFIX: ...
+func (l Linter) Lint(content string) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0)
+
+	lines, _, _ := splitLines(content)
+	suppressedFile := collectSuppressions(lines, DISABLE_DIRECTIVE)
+
+	var comments []string
+	for idx, line := range lines {
+		switch trimmed := strings.TrimSpace(line); {
+		case trimmed == "":
+			comments = nil
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			comments = append(comments, trimmed)
+			continue
+		}
+
+		suppressedLine := collectSuppressions(comments, IGNORE_NEXT_LINE_DIRECTIVE)
+		comments = nil
+
+		for _, check := range l.checks {
+			id := string(check.ID)
+			if l.disabled[check.ID] || suppressedFile.covers(id) || suppressedLine.covers(id) {
+				continue
+			}
+
+			if message, found := check.Check(line); found {
+				diagnostics = append(diagnostics, Diagnostic{
+					Check:    check.ID,
+					Severity: check.Severity,
+					Line:     idx + 1,
+					Message:  message,
+				})
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+// MARK: Built-in checks
+
+// Strips leading whitespace and the negation prefix, leaving what git matches against
+func lintPattern(line string) string {
+	return strings.TrimPrefix(strings.TrimLeft(line, " \t"), EXCLUDE_PREFIX)
+}
+
+func checkTrailingWhitespace(line string) (string, bool) {
+	// git and Parse only strip unescaped trailing spaces, trailing tabs are part of the pattern
+	line = strings.TrimLeft(line, " \t")
+	if trimWhitespace(line) == line {
+		return "", false // no trailing spaces, or the last one is escaped
+	}
+
+	return "trailing spaces are stripped by git, escape them with a backslash if they are part of the name", true
+}
+
+// Characters that may follow a backslash in a pattern to escape them
+const escapableCharacters = "*?[]!# \\"
+
+func checkBackslashPath(line string) (string, bool) {
+	pattern := lintPattern(line)
+
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '\\' {
+			continue
+		}
+
+		if i+1 < len(pattern) && strings.IndexByte(escapableCharacters, pattern[i+1]) != -1 {
+			i++ // escape sequence
+			continue
+		}
+
+		return "backslash used as a path separator, patterns always use '/'", true
+	}
+
+	return "", false
+}
+
+func checkMidSegmentDoubleStar(line string) (string, bool) {
+	for _, segment := range strings.Split(lintPattern(line), "/") {
+		if strings.Contains(segment, "**") && segment != "**" {
+			return fmt.Sprintf("'**' in %q is not a whole path segment and behaves like a single '*'", segment), true
+		}
+	}
+
+	return "", false
+}
+
+func checkUnterminatedCharacterClass(line string) (string, bool) {
+	pattern := lintPattern(line)
+
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++ // skip escaped character
+		case '[':
+			end := characterClassEnd(pattern, i)
+			if end == -1 {
+				return "character class opened with '[' is never closed, so the pattern never matches", true
+			}
+			i = end
+		}
+	}
+
+	return "", false
+}
+
+// Returns the index of the "]" closing the character class that starts at pattern[p], or -1
+func characterClassEnd(pattern string, p int) int {
+	i := p + 1
+	if i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^') {
+		i++
+	}
+
+	for first := true; i < len(pattern); first = false {
+		switch {
+		case pattern[i] == ']' && !first:
+			return i
+		case pattern[i] == '\\':
+			i += 2
+		case strings.HasPrefix(pattern[i:], "[:") && strings.Contains(pattern[i+2:], ":]"):
+			i += strings.Index(pattern[i+2:], ":]") + 4
+		default:
+			i++
+		}
+	}
+
+	return -1
+}
+
+var absolutePathPattern = regexp.MustCompile(`^([A-Za-z]:[\\/]|\\\\|~/|/(home|Users)/)`)
+
+func checkAbsolutePath(line string) (string, bool) {
+	if absolutePathPattern.MatchString(lintPattern(line)) {
+		return "pattern looks like an absolute file system path, but patterns are relative to the ignore file", true
+	}
+
+	return "", false
+}
+
+func checkDotSlashPrefix(line string) (string, bool) {
+	pattern := lintPattern(line)
+	if strings.HasPrefix(pattern, "./") || strings.HasPrefix(pattern, "../") {
+		return "patterns starting with './' or '../' never match, use a leading '/' to anchor to the ignore file's directory", true
+	}
+
+	if strings.Contains(pattern, "/./") || strings.Contains(pattern, "/../") {
+		return "patterns with './' or '../' segments never match, git does not resolve them", true
+	}
+
+	return "", false
+}
+
+func checkInvalidUTF8(line string) (string, bool) {
+	if utf8.ValidString(line) {
+		return "", false
+	}
+
+	return "line is not valid UTF-8, git matches its bytes as they are, check the file's encoding", true
+}
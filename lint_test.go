@@ -0,0 +1,163 @@
+package gignore
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTrailingWhitespaceAdvice(t *testing.T) {
+	line := `name\ `
+	if _, found := checkTrailingWhitespace(line); found {
+		t.Fatalf("expected %q to be accepted", line)
+	}
+
+	ignoreFile := NewIgnoreFile()
+	checkErrors("", Parse(line+"\n", &ignoreFile), t)
+
+	if rules := ignoreFile.Rules(); len(rules) != 1 || rules[0].Pattern() != line {
+		t.Errorf("expected the escaped space to be kept when parsing, got %v", rules)
+	}
+
+	// git matches a trailing tab as part of the name, so it is neither reported nor stripped
+	tab := "name\t"
+	if _, found := checkTrailingWhitespace(tab); found {
+		t.Errorf("expected %q to be accepted", tab)
+	}
+
+	tabFile := NewIgnoreFile()
+	checkErrors("", Parse(tab+"\n", &tabFile), t)
+
+	if rules := tabFile.Rules(); len(rules) != 1 || rules[0].Pattern() != tab {
+		t.Errorf("expected the trailing tab to be kept when parsing, got %v", rules)
+	}
+}
+
+func TestLintChecks(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		check LintCheckID
+		found bool
+	}{
+		{name: "Fail-TrailingWhitespace", line: "*.log  ", check: TRAILING_WHITESPACE, found: true},
+		{name: "Pass-EscapedTrailingWhitespace", line: `name\ `, check: TRAILING_WHITESPACE, found: false},
+		{name: "Fail-WhitespaceAfterEscapedSpace", line: `name\  `, check: TRAILING_WHITESPACE, found: true},
+		{name: "Fail-WhitespaceAfterEscapedBackslash", line: `name\\ `, check: TRAILING_WHITESPACE, found: true},
+		{name: "Pass-TrailingTab", line: "name\t", check: TRAILING_WHITESPACE, found: false},
+		{name: "Fail-SpaceAfterTrailingTab", line: "name\t ", check: TRAILING_WHITESPACE, found: true},
+		{name: "Fail-Backslash", line: `build\output`, check: BACKSLASH_PATH, found: true},
+		{name: "Pass-EscapedGlob", line: `\*.log`, check: BACKSLASH_PATH, found: false},
+		{name: "Fail-MidSegmentDoubleStar", line: "src/a**b", check: MID_SEGMENT_DOUBLE_STAR, found: true},
+		{name: "Pass-DoubleStarSegment", line: "src/**/b", check: MID_SEGMENT_DOUBLE_STAR, found: false},
+		{name: "Fail-UnterminatedClass", line: "file[0-9.txt", check: UNTERMINATED_CHARACTER_CLASS, found: true},
+		{name: "Pass-TerminatedClass", line: "file[]0-9].txt", check: UNTERMINATED_CHARACTER_CLASS, found: false},
+		{name: "Pass-EscapedBracket", line: `file\[1.txt`, check: UNTERMINATED_CHARACTER_CLASS, found: false},
+		{name: "Fail-WindowsAbsolutePath", line: `C:\Users\me\secrets.txt`, check: ABSOLUTE_PATH, found: true},
+		{name: "Fail-HomeDirectory", line: "/home/me/.cache/", check: ABSOLUTE_PATH, found: true},
+		{name: "Pass-AnchoredPattern", line: "/build", check: ABSOLUTE_PATH, found: false},
+		{name: "Fail-DotSlash", line: "!./build", check: DOT_SLASH_PREFIX, found: true},
+		{name: "Pass-DotFile", line: ".env", check: DOT_SLASH_PREFIX, found: false},
+		{name: "Fail-DotSlashSegment", line: "src/./gen", check: DOT_SLASH_PREFIX, found: true},
+		{name: "Fail-InvalidUTF8", line: "caf\xe9.txt", check: INVALID_UTF8, found: true},
+		{name: "Pass-UTF8", line: "café.txt", check: INVALID_UTF8, found: false},
+	}
+
+	checks := make(map[LintCheckID]LintCheck)
+	for _, check := range DefaultLintChecks() {
+		checks[check.ID] = check
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, found := checks[tc.check].Check(tc.line)
+			if found != tc.found {
+				t.Errorf("expected %s to report %t for %q, got %t", tc.check, tc.found, tc.line, found)
+			}
+		})
+	}
+}
+
+func TestLinter(t *testing.T) {
+	content := "# comment with trailing space \n*.log \n\n./build/\n"
+
+	linter := NewLinter()
+	diagnostics := linter.Lint(content)
+
+	expected := []Diagnostic{
+		{Check: TRAILING_WHITESPACE, Severity: WARNING, Line: 2},
+		{Check: DOT_SLASH_PREFIX, Severity: ERROR, Line: 4},
+	}
+
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %d: %v", len(expected), len(diagnostics), diagnostics)
+	}
+
+	for idx, diagnostic := range diagnostics {
+		if diagnostic.Check != expected[idx].Check || diagnostic.Severity != expected[idx].Severity || diagnostic.Line != expected[idx].Line {
+			t.Errorf("expected %v, got %v", expected[idx], diagnostic)
+		}
+	}
+
+	if err := linter.Disable(TRAILING_WHITESPACE); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if diagnostics := linter.Lint(content); len(diagnostics) != 1 {
+		t.Errorf("expected 1 diagnostic with TRAILING_WHITESPACE disabled, got %d", len(diagnostics))
+	}
+
+	if err := linter.Enable(TRAILING_WHITESPACE); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if !linter.Enabled(TRAILING_WHITESPACE) {
+		t.Errorf("expected TRAILING_WHITESPACE to be enabled")
+	}
+
+	if err := linter.Disable("NOT_A_CHECK"); !errors.Is(err, unknownLintCheckError) {
+		t.Errorf("expected unknown lint check error, got %v", err)
+	}
+}
+
+func TestLinterEncoding(t *testing.T) {
+	content := "\uFEFF*.log\r./build/\r\ncaf\xe9.txt\r\n"
+
+	diagnostics := NewLinter().Lint(content)
+
+	expected := []Diagnostic{
+		{Check: DOT_SLASH_PREFIX, Severity: ERROR, Line: 2},
+		{Check: INVALID_UTF8, Severity: WARNING, Line: 3},
+	}
+
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %d: %v", len(expected), len(diagnostics), diagnostics)
+	}
+
+	for idx, diagnostic := range diagnostics {
+		if diagnostic.Check != expected[idx].Check || diagnostic.Severity != expected[idx].Severity || diagnostic.Line != expected[idx].Line {
+			t.Errorf("expected %v, got %v", expected[idx], diagnostic)
+		}
+	}
+}
+
+func TestLinterRegister(t *testing.T) {
+	const NO_TODO LintCheckID = "NO_TODO"
+
+	linter := NewLinter()
+	linter.Register(LintCheck{
+		ID:       NO_TODO,
+		Severity: INFO,
+		Check: func(line string) (string, bool) {
+			return "todo files should be committed", line == "TODO"
+		},
+	})
+
+	diagnostics := linter.Lint("TODO\n*.log")
+	if len(diagnostics) != 1 || diagnostics[0].Check != NO_TODO {
+		t.Fatalf("expected a single NO_TODO diagnostic, got %v", diagnostics)
+	}
+
+	if log := diagnostics[0].Log(); log != "1: INFO NO_TODO: todo files should be committed" {
+		t.Errorf("unexpected log output %q", log)
+	}
+}
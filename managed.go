@@ -0,0 +1,144 @@
+package gignore
+
+import (
+	"errors"
+	"strings"
+)
+
+// MARK: Markers
+const (
+	MANAGED_BLOCK_BEGIN = "# BEGIN gignore:managed"
+	MANAGED_BLOCK_END   = "# END gignore:managed"
+)
+
+var (
+	unterminatedManagedBlockError  = errors.New("managed block begin marker has no matching end marker")
+	unexpectedManagedBlockEndError = errors.New("managed block end marker found before begin marker")
+	duplicateManagedBlockError     = errors.New("only one managed block is supported per file")
+)
+
+// managedBlock holds the human-maintained content surrounding a managed block.
+// The lines are kept verbatim so they can be written back untouched, and the rules
+// parsed from them are kept so conflict analysis can still take them into account.
+type managedBlock struct {
+	before      []string
+	after       []string
+	beforeRules []Ruler
+	afterRules  []Ruler
+	beforeMeta  []ruleMeta // metadata of beforeRules
+	afterMeta   []ruleMeta // metadata of afterRules
+}
+
+func isManagedBlockBegin(line string) bool {
+	return strings.TrimSpace(line) == MANAGED_BLOCK_BEGIN
+}
+
+func isManagedBlockEnd(line string) bool {
+	return strings.TrimSpace(line) == MANAGED_BLOCK_END
+}
+
+// Returns the line indexes of the begin and end markers, or -1 for both if there is no managed block
+func findManagedBlock(lines []string) (int, int, error) {
+	begin, end := -1, -1
+
+	for idx, line := range lines {
+		switch {
+		case isManagedBlockBegin(line):
+			if begin != -1 {
+				return -1, -1, duplicateManagedBlockError
+			}
+			begin = idx
+		case isManagedBlockEnd(line):
+			if begin == -1 {
+				return -1, -1, unexpectedManagedBlockEndError
+			}
+			if end != -1 {
+				return -1, -1, duplicateManagedBlockError
+			}
+			end = idx
+		}
+	}
+
+	if begin != -1 && end == -1 {
+		return -1, -1, unterminatedManagedBlockError
+	}
+
+	return begin, end, nil
+}
+
+// Returns every rule in file order, including the rules outside of a managed block
+func (f IgnoreFile) allRules() []Ruler {
+	if f.block == nil {
+		return f.rules
+	}
+
+	rules := make([]Ruler, 0, len(f.block.beforeRules)+len(f.rules)+len(f.block.afterRules))
+	rules = append(rules, f.block.beforeRules...)
+	rules = append(rules, f.rules...)
+	rules = append(rules, f.block.afterRules...)
+
+	return rules
+}
+
+// Returns the metadata of every rule, in the order of allRules
+func (f IgnoreFile) allMeta() []ruleMeta {
+	managed := make([]ruleMeta, len(f.rules))
+	copy(managed, f.meta)
+
+	if f.block == nil {
+		return managed
+	}
+
+	metas := make([]ruleMeta, 0, len(f.block.beforeMeta)+len(managed)+len(f.block.afterMeta))
+	metas = append(metas, f.block.beforeMeta...)
+	metas = append(metas, managed...)
+	metas = append(metas, f.block.afterMeta...)
+
+	return metas
+}
+
+// Reports whether the rule at idx of allRules is one of the rules inside the managed block,
+// which tells copies of a rule inside and outside of the block apart
+func (f IgnoreFile) managedAt(idx int) bool {
+	if f.block == nil {
+		return true
+	}
+
+	return idx >= len(f.block.beforeRules) && idx < len(f.block.beforeRules)+len(f.rules)
+}
+
+// Reports whether a rule lives in the part of the file gignore is allowed to modify
+func (f *IgnoreFile) isManaged(rule Ruler) bool {
+	return f.findRuleIndex(rule) != -1
+}
+
+// HasManagedBlock reports whether the IgnoreFile only manages a marked block of a larger,
+// human-maintained file.
+func (f IgnoreFile) HasManagedBlock() bool {
+	return f.block != nil
+}
+
+// EnableManagedBlock wraps the IgnoreFile's rules in a managed block. Once rendered, the
+// rules appear between MANAGED_BLOCK_BEGIN and MANAGED_BLOCK_END markers, and anything
+// added outside of the markers by hand is preserved on later loads and saves.
+//
+// The method is a no-op if the IgnoreFile already has a managed block.
+//
+// Example:
+//
+//	ignoreFile := NewIgnoreFile()
+//	ignoreFile.EnableManagedBlock()
+//	ignoreFile.AddExtension("log", INCLUDE)
+//
+//	fmt.Print(Render(&ignoreFile, RenderOptions{}))
+//	// Output:
+//	// # BEGIN gignore:managed
+//	// *.log
+//	// # END gignore:managed
+func (f *IgnoreFile) EnableManagedBlock() {
+	if f.block != nil {
+		return
+	}
+
+	f.block = &managedBlock{}
+}
@@ -0,0 +1,307 @@
+package gignore
+
+import "testing"
+
+func TestParseManagedBlock(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		managed      []Ruler
+		errorMessage string
+	}{
+		{
+			name: "Pass",
+			content: `# Maintained by hand
+*.log
+
+# BEGIN gignore:managed
+build/**
+!build/important.txt
+# END gignore:managed
+todo.md
+`,
+			managed: []Ruler{
+				DirectoryRule{name: "build", mode: RECURSIVE, act: INCLUDE},
+				FileRule{path: "build/important.txt", act: EXCLUDE},
+			},
+			errorMessage: "",
+		},
+		{
+			name:         "Fail-Unterminated",
+			content:      "*.log\n# BEGIN gignore:managed\nbuild/**\n",
+			errorMessage: unterminatedManagedBlockError.Error(),
+		},
+		{
+			name:         "Fail-EndBeforeBegin",
+			content:      "# END gignore:managed\n# BEGIN gignore:managed\n",
+			errorMessage: unexpectedManagedBlockEndError.Error(),
+		},
+		{
+			name:         "Fail-Duplicate",
+			content:      "# BEGIN gignore:managed\n# END gignore:managed\n# BEGIN gignore:managed\n# END gignore:managed\n",
+			errorMessage: duplicateManagedBlockError.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore := NewIgnoreFile()
+			err := Parse(tc.content, &ignore)
+
+			checkErrors(tc.errorMessage, err, t)
+			if tc.errorMessage != "" {
+				return
+			}
+
+			if !ignore.HasManagedBlock() {
+				t.Errorf("expected a managed block")
+			}
+
+			if len(ignore.Rules()) != len(tc.managed) {
+				t.Errorf("expected %d managed rules, found %d", len(tc.managed), len(ignore.Rules()))
+				return
+			}
+
+			for idx, rule := range ignore.Rules() {
+				if !rulesEqual(tc.managed[idx], rule) {
+					t.Errorf("Expected rule %s, found %s", tc.managed[idx].Render(), rule.Render())
+				}
+			}
+
+			if rendered := Render(&ignore, RenderOptions{TrailingNewLine: true}); rendered != tc.content {
+				t.Errorf("expected round trip to preserve content:\n%s\ngot:\n%s", tc.content, rendered)
+			}
+		})
+	}
+}
+
+func TestManagedBlockOperations(t *testing.T) {
+	content := `*.log
+# BEGIN gignore:managed
+build/**
+# END gignore:managed
+`
+
+	tests := []struct {
+		name         string
+		operation    Operation
+		errorMessage string
+		expected     string
+	}{
+		{
+			name:      "Pass-AddInsideBlock",
+			operation: AddFileOperation("todo.md", INCLUDE),
+			expected: `*.log
+# BEGIN gignore:managed
+build/**
+todo.md
+# END gignore:managed
+`,
+		},
+		{
+			name:         "Fail-UnreachableBecauseOfUnmanagedRule",
+			operation:    AddFileOperation("debug.log", INCLUDE),
+			errorMessage: "operation 0: " + unreachableRuleError.Error(),
+		},
+		{
+			name:         "Fail-CannotDeleteUnmanagedRule",
+			operation:    DeleteExtensionOperation("log", INCLUDE),
+			errorMessage: "operation 0: " + ruleNotFoundError.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore := NewIgnoreFile()
+			if err := Parse(content, &ignore); err != nil {
+				t.Fatalf("unexpected error parsing: %s", err.Error())
+			}
+
+			_, err := ignore.Batch(tc.operation)
+			checkErrors(tc.errorMessage, err, t)
+			if tc.errorMessage != "" {
+				return
+			}
+
+			if rendered := Render(&ignore, RenderOptions{TrailingNewLine: true}); rendered != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, rendered)
+			}
+		})
+	}
+}
+
+func TestManagedBlockConflicts(t *testing.T) {
+	content := `*.log
+# BEGIN gignore:managed
+*.log
+# END gignore:managed
+build/
+build/**
+`
+
+	ignore := NewIgnoreFile()
+	if err := Parse(content, &ignore); err != nil {
+		t.Fatalf("unexpected error parsing: %s", err.Error())
+	}
+
+	if conflicts := ignore.FindConflicts(); len(conflicts) != 2 {
+		t.Errorf("expected 2 conflicts, found %d", len(conflicts))
+	}
+
+	results, err := ignore.FixConflicts(1)
+	checkErrors("", err, t)
+
+	if len(results) != 2 || results[0].Result != REMOVED || results[1].Result != REVIEW_RECOMMENDED {
+		t.Errorf("expected the managed duplicate to be removed and the unmanaged conflict to be flagged, got %v", results)
+	}
+
+	if len(ignore.Rules()) != 0 {
+		t.Errorf("expected managed duplicate to be removed")
+	}
+
+	if len(ignore.block.afterRules) != 2 {
+		t.Errorf("expected unmanaged rules to be untouched")
+	}
+}
+
+func TestManagedBlockReviewReportedOnce(t *testing.T) {
+	content := `*.log
+debug.log
+# BEGIN gignore:managed
+# END gignore:managed
+`
+
+	ignore := NewIgnoreFile()
+	if err := Parse(content, &ignore); err != nil {
+		t.Fatalf("unexpected error parsing: %s", err.Error())
+	}
+
+	results, err := ignore.AddFile("foo.txt", INCLUDE)
+	checkErrors("", err, t)
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %v", len(results), results)
+	}
+
+	if results[0].Result != ADDED || results[1].Result != REVIEW_RECOMMENDED || results[1].Rule.Render() != "debug.log" {
+		t.Errorf("expected the rule to be added and the unmanaged conflict to be flagged once, got %v", results)
+	}
+
+	results, err = ignore.FixConflicts(20)
+	checkErrors("", err, t)
+
+	if len(results) != 1 {
+		t.Errorf("expected 1 result, got %d: %v", len(results), results)
+	}
+}
+
+func TestManagedBlockEnsurePresent(t *testing.T) {
+	content := `*.log
+# BEGIN gignore:managed
+build/**
+# END gignore:managed
+*.tmp
+`
+
+	tests := []struct {
+		name         string
+		rule         Ruler
+		errorMessage string
+		result       ActionResult
+		expected     string
+	}{
+		{
+			name:   "Pass-PresentBeforeBlock",
+			rule:   ExtensionRule{ext: "log", act: INCLUDE},
+			result: UNCHANGED,
+		},
+		{
+			name:   "Pass-PresentAfterBlock",
+			rule:   ExtensionRule{ext: "tmp", act: INCLUDE},
+			result: UNCHANGED,
+		},
+		{
+			name:   "Pass-Added",
+			rule:   FileRule{path: "todo.md", act: INCLUDE},
+			result: ADDED,
+			expected: `*.log
+# BEGIN gignore:managed
+build/**
+todo.md
+# END gignore:managed
+*.tmp
+`,
+		},
+		{
+			name:         "Fail-SemanticConflictAfterBlock",
+			rule:         ExtensionRule{ext: "tmp", act: EXCLUDE},
+			errorMessage: semanticConflictError.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore := NewIgnoreFile()
+			if err := Parse(content, &ignore); err != nil {
+				t.Fatalf("unexpected error parsing: %s", err.Error())
+			}
+
+			results, err := ignore.EnsurePresent(tc.rule)
+			checkErrors(tc.errorMessage, err, t)
+			if tc.errorMessage != "" {
+				return
+			}
+
+			if len(results) != 1 || results[0].Result != tc.result {
+				t.Fatalf("expected a single %s result, got %v", tc.result, results)
+			}
+
+			expected := tc.expected
+			if expected == "" {
+				expected = content
+			}
+
+			if rendered := Render(&ignore, RenderOptions{TrailingNewLine: true}); rendered != expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", expected, rendered)
+			}
+		})
+	}
+}
+
+func TestManagedBlockReconcileIdempotent(t *testing.T) {
+	content := `*.log
+# BEGIN gignore:managed
+build/**
+# END gignore:managed
+*.tmp
+`
+
+	ignore := NewIgnoreFile()
+	if err := Parse(content, &ignore); err != nil {
+		t.Fatalf("unexpected error parsing: %s", err.Error())
+	}
+
+	desired := NewDesiredState(false,
+		ExtensionRule{ext: "log", act: INCLUDE},
+		DirectoryRule{name: "build", mode: RECURSIVE, act: INCLUDE},
+		ExtensionRule{ext: "tmp", act: INCLUDE},
+	)
+
+	results, err := ignore.Reconcile(desired)
+	checkErrors("", err, t)
+
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %v", results)
+	}
+}
+
+func TestEnableManagedBlock(t *testing.T) {
+	ignore := NewIgnoreFile()
+	ignore.AddExtension("log", INCLUDE)
+	ignore.EnableManagedBlock()
+
+	expected := MANAGED_BLOCK_BEGIN + "\n*.log\n" + MANAGED_BLOCK_END
+	if rendered := Render(&ignore, RenderOptions{}); rendered != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, rendered)
+	}
+}
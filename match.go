@@ -0,0 +1,305 @@
+package gignore
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// Match describes the rule that decides whether a path is ignored, using the same precedence
+// as git: the last matching rule wins, and a path inside an ignored directory is ignored by the
+// rule that matched the directory, no matter what rules follow.
+type Match struct {
+	// Path is the path as it was checked
+	Path string
+	// Source is the ignore file the rule was loaded from, if known
+	Source string
+	// Rule is the rule that decided the outcome, or nil if no rule matches the path
+	Rule Ruler
+	// Line is the line the rule was parsed from, or 0 if unknown
+	Line int
+}
+
+// Matched reports whether any rule matches the path, including negated rules.
+func (m Match) Matched() bool {
+	return m.Rule != nil
+}
+
+// Ignored reports whether the path is ignored.
+func (m Match) Ignored() bool {
+	return m.Rule != nil && m.Rule.Action() == INCLUDE
+}
+
+// Log returns the Match in the format of `git check-ignore -v`: "source:line:pattern<TAB>path".
+// Paths that match no rule are returned as "::<TAB>path", like `git check-ignore -v --non-matching`.
+//
+// Example output: ".gitignore:3:*.log	debug.log"
+func (m Match) Log() string {
+	if m.Rule == nil {
+		return fmt.Sprintf("::\t%s", m.Path)
+	}
+
+	return fmt.Sprintf("%s:%d:%s\t%s", m.Source, m.Line, m.Rule.Render(), m.Path)
+}
+
+// Match reports which rule, if any, decides whether a path is ignored. Paths are relative to
+// the directory of the ignore file, use "/" as separator, and a trailing "/" marks a directory
+// (rules such as "build/" only match directories).
+//
+// Parameters:
+//   - target: The path to check, e.g. "build/output.bin" or "node_modules/".
+//
+// Returns a Match describing the deciding rule. If no rule matches, Match.Rule is nil.
+// If the IgnoreFile has a managed block, rules outside of the block are taken into account.
+//
+// Example:
+//
+//	match := ignoreFile.Match("build/output.bin")
+//	if match.Ignored() {
+//	    fmt.Printf("ignored by line %d: %s\n", match.Line, match.Rule.Render())
+//	}
+func (f IgnoreFile) Match(target string) Match {
+	match := Match{Path: target}
+
+	isDir := strings.HasSuffix(target, "/")
+	cleaned := cleanMatchPath(target)
+	if cleaned == "" {
+		return match
+	}
+
+	rules := f.allRules()
+	if idx := decidingRule(rules, cleaned, isDir); idx != -1 {
+		match.Source = f.source
+		match.Rule = rules[idx]
+		match.Line = f.allLines()[idx]
+	}
+
+	return match
+}
+
+// IsIgnored reports whether a path is ignored. See Match for how paths are interpreted.
+func (f IgnoreFile) IsIgnored(target string) bool {
+	return f.Match(target).Ignored()
+}
+
+func cleanMatchPath(target string) string {
+	target = strings.TrimPrefix(path.Clean("/"+target), "/")
+	if target == "." {
+		return ""
+	}
+
+	return target
+}
+
+// Returns the index of the rule that decides whether a cleaned path is ignored, or -1 if no rule matches
+func decidingRule(rules []Ruler, target string, isDir bool) int {
+	// A path inside an ignored directory can't be re-included, so the directory's rule decides
+	parts := strings.Split(target, "/")
+	for i := 1; i < len(parts); i++ {
+		idx := lastMatchingRule(rules, strings.Join(parts[:i], "/"), true)
+		if idx != -1 && rules[idx].Action() == INCLUDE {
+			return idx
+		}
+	}
+
+	return lastMatchingRule(rules, target, isDir)
+}
+
+func lastMatchingRule(rules []Ruler, target string, isDir bool) int {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if matchesRule(rules[i], target, isDir) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Reports whether a rule's pattern matches a cleaned path, following gitignore semantics
+func matchesRule(rule Ruler, target string, isDir bool) bool {
+	pattern := rule.Pattern()
+
+	if strings.HasSuffix(pattern, "/") {
+		if !isDir {
+			return false
+		}
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+
+	// Patterns without a separator match the name at any depth
+	if !strings.Contains(pattern, "/") {
+		return wildmatch(pattern, path.Base(target))
+	}
+
+	return wildmatch(strings.TrimPrefix(pattern, "/"), target)
+}
+
+// MARK: Wildmatch
+// wildmatch matches text against a gitignore glob: "*" and "?" never match "/", "[...]" is a
+// character class, "\" escapes the next character, and "**" matches across directories when it
+// makes up a whole path segment ("**/a", "a/**", "a/**/b").
+func wildmatch(pattern, text string) bool {
+	return wildmatchFrom(pattern, 0, text, 0)
+}
+
+func wildmatchFrom(pattern string, p int, text string, t int) bool {
+	for p < len(pattern) {
+		switch pattern[p] {
+		case '*':
+			start := p
+			for p < len(pattern) && pattern[p] == '*' {
+				p++
+			}
+
+			segmentStart := start == 0 || pattern[start-1] == '/'
+			segmentEnd := p == len(pattern) || pattern[p] == '/'
+
+			if p-start >= 2 && segmentStart && segmentEnd {
+				if p == len(pattern) {
+					return true // trailing "/**" matches everything inside
+				}
+
+				// "**/" matches zero or more directories
+				p++
+				for i := t; i <= len(text); i++ {
+					if (i == t || text[i-1] == '/') && wildmatchFrom(pattern, p, text, i) {
+						return true
+					}
+				}
+
+				return false
+			}
+
+			for i := t; i <= len(text); i++ {
+				if wildmatchFrom(pattern, p, text, i) {
+					return true
+				}
+				if i < len(text) && text[i] == '/' {
+					return false
+				}
+			}
+
+			return false
+		case '?':
+			if t >= len(text) || text[t] == '/' {
+				return false
+			}
+
+			_, size := utf8.DecodeRuneInString(text[t:])
+			p, t = p+1, t+size
+		case '[':
+			if t >= len(text) || text[t] == '/' {
+				return false
+			}
+
+			r, size := utf8.DecodeRuneInString(text[t:])
+			next, ok := matchClass(pattern, p, r)
+			if !ok {
+				return false
+			}
+
+			p, t = next, t+size
+		default:
+			if pattern[p] == '\\' && p+1 < len(pattern) {
+				p++
+			}
+
+			if t >= len(text) || pattern[p] != text[t] {
+				return false
+			}
+
+			p, t = p+1, t+1
+		}
+	}
+
+	return t == len(text)
+}
+
+var characterClasses = map[string]func(rune) bool{
+	"alnum":  func(r rune) bool { return isAlpha(r) || isDigit(r) },
+	"alpha":  isAlpha,
+	"blank":  func(r rune) bool { return r == ' ' || r == '\t' },
+	"digit":  isDigit,
+	"lower":  func(r rune) bool { return r >= 'a' && r <= 'z' },
+	"punct":  func(r rune) bool { return r < 128 && r > ' ' && r != 127 && !isAlpha(r) && !isDigit(r) },
+	"space":  func(r rune) bool { return strings.ContainsRune(" \t\n\r\v\f", r) },
+	"upper":  func(r rune) bool { return r >= 'A' && r <= 'Z' },
+	"xdigit": func(r rune) bool { return isDigit(r) || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F') },
+}
+
+func isAlpha(r rune) bool { return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') }
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+
+// Matches r against the character class starting at pattern[p] and returns the index after
+// the class. An unterminated class never matches
+func matchClass(pattern string, p int, r rune) (int, bool) {
+	p++ // skip "["
+
+	negated := false
+	if p < len(pattern) && (pattern[p] == '!' || pattern[p] == '^') {
+		negated = true
+		p++
+	}
+
+	matched := false
+	for first := true; ; first = false {
+		if p >= len(pattern) {
+			return 0, false
+		}
+
+		if pattern[p] == ']' && !first {
+			p++
+			break
+		}
+
+		if strings.HasPrefix(pattern[p:], "[:") {
+			if end := strings.Index(pattern[p+2:], ":]"); end != -1 {
+				class, ok := characterClasses[pattern[p+2:p+2+end]]
+				if !ok {
+					return 0, false
+				}
+
+				matched = matched || class(r)
+				p += end + 4
+				continue
+			}
+		}
+
+		low, size := classRune(pattern, p)
+		if size == 0 {
+			return 0, false
+		}
+		p += size
+
+		high := low
+		if p+1 < len(pattern) && pattern[p] == '-' && pattern[p+1] != ']' {
+			high, size = classRune(pattern, p+1)
+			if size == 0 {
+				return 0, false
+			}
+			p += size + 1
+		}
+
+		if low <= r && r <= high {
+			matched = true
+		}
+	}
+
+	return p, matched != negated
+}
+
+// Decodes the (possibly escaped) rune at pattern[p] and returns it with the number of bytes consumed
+func classRune(pattern string, p int) (rune, int) {
+	if pattern[p] == '\\' {
+		if p+1 >= len(pattern) {
+			return 0, 0
+		}
+
+		r, size := utf8.DecodeRuneInString(pattern[p+1:])
+		return r, size + 1
+	}
+
+	r, size := utf8.DecodeRuneInString(pattern[p:])
+	return r, size
+}
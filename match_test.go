@@ -0,0 +1,127 @@
+package gignore
+
+import "testing"
+
+func TestWildmatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		text     string
+		expected bool
+	}{
+		{pattern: "*.log", text: "debug.log", expected: true},
+		{pattern: "*.log", text: "logs/debug.log", expected: false},
+		{pattern: "build/*", text: "build/output", expected: true},
+		{pattern: "build/*", text: "build/nested/output", expected: false},
+		{pattern: "build/**", text: "build/nested/output", expected: true},
+		{pattern: "build/**", text: "build", expected: false},
+		{pattern: "**/temp", text: "temp", expected: true},
+		{pattern: "**/temp", text: "a/b/temp", expected: true},
+		{pattern: "a/**/b", text: "a/b", expected: true},
+		{pattern: "a/**/b", text: "a/x/y/b", expected: true},
+		{pattern: "a**b", text: "axyb", expected: true},
+		{pattern: "a**b", text: "ax/yb", expected: false},
+		{pattern: "file?.txt", text: "file1.txt", expected: true},
+		{pattern: "file?.txt", text: "file/.txt", expected: false},
+		{pattern: "file[0-9].txt", text: "file7.txt", expected: true},
+		{pattern: "file[!0-9].txt", text: "file7.txt", expected: false},
+		{pattern: "file[[:alpha:]].txt", text: "filex.txt", expected: true},
+		{pattern: "file[]].txt", text: "file].txt", expected: true},
+		{pattern: "file[0-9.txt", text: "file7.txt", expected: false},
+		{pattern: `\*.txt`, text: "*.txt", expected: true},
+		{pattern: `\*.txt`, text: "a.txt", expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.pattern+"/"+tc.text, func(t *testing.T) {
+			if result := wildmatch(tc.pattern, tc.text); result != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	content := `# Logs
+*.log
+!keep.log
+
+build/
+/docs/*.md
+**/tmp/**
+!build/keep.txt` + "\ntab\t"
+
+	ignore := NewIgnoreFile()
+	Parse(content, &ignore)
+
+	tests := []struct {
+		name    string
+		path    string
+		rule    string
+		line    int
+		ignored bool
+	}{
+		{name: "Pass-Extension", path: "logs/debug.log", rule: "*.log", line: 2, ignored: true},
+		{name: "Pass-Negated", path: "keep.log", rule: "!keep.log", line: 3, ignored: false},
+		{name: "Pass-TrailingTab", path: "tab\t", rule: "tab\t", line: 9, ignored: true},
+		{name: "Pass-DirectoryOnly", path: "build/", rule: "build/", line: 5, ignored: true},
+		{name: "Pass-FileNamedLikeDirectory", path: "build", rule: "", line: 0, ignored: false},
+		{name: "Pass-InsideIgnoredDirectory", path: "build/keep.txt", rule: "build/", line: 5, ignored: true},
+		{name: "Pass-Anchored", path: "docs/readme.md", rule: "/docs/*.md", line: 6, ignored: true},
+		{name: "Pass-AnchoredNested", path: "docs/guide/readme.md", rule: "", line: 0, ignored: false},
+		{name: "Pass-Anywhere", path: "src/tmp/cache/x", rule: "**/tmp/**", line: 7, ignored: true},
+		{name: "Pass-Cleaned", path: "./logs/../debug.log", rule: "*.log", line: 2, ignored: true},
+		{name: "Pass-NoMatch", path: "main.go", rule: "", line: 0, ignored: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			match := ignore.Match(tc.path)
+
+			rule := ""
+			if match.Matched() {
+				rule = match.Rule.Render()
+			}
+
+			if rule != tc.rule {
+				t.Errorf("expected rule %q, got %q", tc.rule, rule)
+			}
+
+			if match.Line != tc.line {
+				t.Errorf("expected line %d, got %d", tc.line, match.Line)
+			}
+
+			if match.Ignored() != tc.ignored {
+				t.Errorf("expected ignored to be %t, got %t", tc.ignored, match.Ignored())
+			}
+		})
+	}
+}
+
+func TestMatchLog(t *testing.T) {
+	rule, _ := NewExtensionRule("log", INCLUDE)
+
+	tests := []struct {
+		name     string
+		match    Match
+		expected string
+	}{
+		{
+			name:     "Pass-Matched",
+			match:    Match{Path: "debug.log", Source: ".gitignore", Rule: rule, Line: 3},
+			expected: ".gitignore:3:*.log\tdebug.log",
+		},
+		{
+			name:     "Pass-NotMatched",
+			match:    Match{Path: "main.go"},
+			expected: "::\tmain.go",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if log := tc.match.Log(); log != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, log)
+			}
+		})
+	}
+}
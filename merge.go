@@ -0,0 +1,414 @@
+package gignore
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Merge combines two versions of an ignore file that were both derived from a common base, the
+// way git merges branches, but at the level of rules instead of lines:
+//   - A rule added on either side is kept, and a rule added on both sides is kept once
+//   - A rule from the base that either side removed is removed
+//   - Rules keep the order of ours, and rules added by theirs are placed after the rule that
+//     preceded them in theirs, so additions at the end of both files end up one after the other
+//
+// Everything else is merged three-way as well, so a change made on either side is never lost:
+//   - The section and the comments of a rule on both sides take the change either side made
+//   - The header, the footer and the lines around a managed block are merged line by line
+//   - Suppression directives are merged like rules, and template records by name: a record
+//     changed on one side only takes that side's version
+//
+// Rules added by theirs bring their section and comments along.
+//
+// Parameters:
+//   - base: The common ancestor of both versions.
+//   - ours: The version being merged into, e.g. the current branch.
+//   - theirs: The version being merged, e.g. the other branch.
+//
+// Returns the merged IgnoreFile, the conflicts the merge introduced and the parts of the file
+// that could not be merged.
+//
+// The conflicts are rules that only conflict once both sides are combined (for example one side
+// ignoring "*.log" and the other re-including "debug.log" before it), which can be reviewed or
+// fixed with FixConflicts. Conflicts that ours or theirs already had on its own are not reported;
+// run FindConflicts on the merged file to see every conflict.
+//
+// A MergeConflict is reported for every part both sides changed in different ways. Conflicting
+// lines are written to the merged file between git's conflict markers, "<<<<<<< ours",
+// "=======" and ">>>>>>> theirs", to be resolved by hand. A rule whose section was changed on both
+// sides keeps the section of ours, and a managed block added or removed on one side only keeps
+// the layout of ours.
+//
+// Example:
+//
+//	merged, conflicts, mergeConflicts := Merge(base, ours, theirs)
+//	for _, conflict := range mergeConflicts {
+//	    fmt.Println(conflict.Log()) // MERGE_CONFLICT: footer changed on both sides
+//	}
+func Merge(base, ours, theirs IgnoreFile) (IgnoreFile, []Conflict, []MergeConflict) {
+	inBase := ruleKeys(base.Rules())
+	inOurs := ruleKeys(ours.Rules())
+	inTheirs := ruleKeys(theirs.Rules())
+
+	// A base rule survives only if neither side removed it, any other rule was added by a side
+	keep := func(rule Ruler) bool {
+		key := ruleKey(rule)
+		if inBase[key] {
+			return inOurs[key] && inTheirs[key]
+		}
+
+		return true
+	}
+
+	mergeConflicts := make([]MergeConflict, 0)
+	report := func(part string, clean bool) {
+		if !clean {
+			mergeConflicts = append(mergeConflicts, MergeConflict{Part: part})
+		}
+	}
+
+	merged := ours.clone()
+	merged.rules = make([]Ruler, 0, len(ours.rules))
+	merged.meta = make([]ruleMeta, 0, len(ours.rules))
+
+	baseMeta, theirsMeta := metaByRule(base), metaByRule(theirs)
+	copies := make(map[string]int)
+
+	for idx, rule := range ours.rules {
+		key := ruleKey(rule)
+		n := copies[key]
+		copies[key]++
+
+		if !keep(rule) {
+			continue
+		}
+
+		meta := ours.metaAt(idx)
+		if theirMeta, ok := nthMeta(theirsMeta, key, n); ok {
+			ancestor, _ := nthMeta(baseMeta, key, n)
+
+			var sectionClean, commentsClean bool
+			meta, sectionClean, commentsClean = mergeRuleMeta(ancestor, meta, theirMeta)
+			report(fmt.Sprintf("section of '%s'", rule.Render()), sectionClean)
+			report(fmt.Sprintf("comments of '%s'", rule.Render()), commentsClean)
+		}
+
+		merged.addRule(rule, meta)
+	}
+
+	anchor := 0
+	for idx, rule := range theirs.rules {
+		if idx := merged.findRuleIndex(rule); idx != -1 {
+			anchor = idx + 1
+			continue
+		}
+
+		if !keep(rule) || merged.containsRule(rule) {
+			continue // removed, or kept outside of the managed block of ours
+		}
+
+		// Rules ours added after the same neighbour come first
+		for anchor < len(merged.rules) && addedBy(merged.rules[anchor], inBase, inTheirs) {
+			anchor++
+		}
+
+		meta := theirs.metaAt(idx)
+		meta.line = 0 // the line numbers of theirs don't apply to the merged file
+		merged.insertAt(anchor, rule, meta)
+		anchor++
+	}
+
+	var clean bool
+	merged.header, clean = mergeLines(base.header, ours.header, theirs.header)
+	report("header", clean)
+	merged.footer, clean = mergeLines(base.footer, ours.footer, theirs.footer)
+	report("footer", clean)
+
+	merged.directives = mergeDirectives(base.directives, ours.directives, theirs.directives)
+	merged.templates = mergeTemplateRecords(base.templates, ours.templates, theirs.templates)
+
+	switch {
+	case ours.block == nil && theirs.block == nil:
+	case ours.block == nil || theirs.block == nil:
+		report("managed block", false)
+	default:
+		var baseBlock managedBlock
+		if base.block != nil {
+			baseBlock = *base.block
+		}
+
+		before, beforeClean := mergeLines(baseBlock.before, ours.block.before, theirs.block.before)
+		report("lines before the managed block", beforeClean)
+		after, afterClean := mergeLines(baseBlock.after, ours.block.after, theirs.block.after)
+		report("lines after the managed block", afterClean)
+
+		merged.block = newManagedBlock(before, after, 0)
+		for idx := range merged.block.afterMeta {
+			merged.block.afterMeta[idx].line = 0 // only known once the merged file is rendered
+		}
+	}
+
+	return merged, newConflicts(merged, ours, theirs), mergeConflicts
+}
+
+// MergeConflict is a part of an ignore file that both sides of a merge changed in different ways,
+// which Merge cannot combine on its own.
+type MergeConflict struct {
+	// Part describes what both sides changed, e.g. "footer" or "comments of 'build/'"
+	Part string
+}
+
+// Log returns a formatted string representation of the MergeConflict suitable for logging
+// or display purposes.
+//
+// Example output: "MERGE_CONFLICT: comments of 'build/' changed on both sides"
+func (c MergeConflict) Log() string {
+	return fmt.Sprintf("MERGE_CONFLICT: %s changed on both sides", c.Part)
+}
+
+// Returns the conflicts of the merged file that neither side had on its own
+func newConflicts(merged, ours, theirs IgnoreFile) []Conflict {
+	existing := make(map[string]bool)
+	for _, side := range []IgnoreFile{ours, theirs} {
+		for _, conflict := range side.FindConflicts() {
+			existing[conflictKey(conflict)] = true
+		}
+	}
+
+	conflicts := make([]Conflict, 0)
+	for _, conflict := range merged.FindConflicts() {
+		if !existing[conflictKey(conflict)] {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	return conflicts
+}
+
+// Reports whether a rule was added by the side other is compared against
+func addedBy(rule Ruler, inBase, inOther map[string]bool) bool {
+	key := ruleKey(rule)
+
+	return !inBase[key] && !inOther[key]
+}
+
+func ruleKeys(rules []Ruler) map[string]bool {
+	keys := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		keys[ruleKey(rule)] = true
+	}
+
+	return keys
+}
+
+// Merges suppression directives like rules: a directive either side removed is removed, and a
+// directive added on either side is kept once
+func mergeDirectives(base, ours, theirs []string) []string {
+	inBase, inOurs, inTheirs := lineSet(base), lineSet(ours), lineSet(theirs)
+
+	merged := make([]string, 0, len(ours))
+	seen := make(map[string]bool, len(ours))
+
+	for _, directive := range append(slices.Clone(ours), theirs...) {
+		if seen[directive] || (inBase[directive] && !(inOurs[directive] && inTheirs[directive])) {
+			continue
+		}
+
+		seen[directive] = true
+		merged = append(merged, directive)
+	}
+
+	return merged
+}
+
+func lineSet(lines []string) map[string]bool {
+	set := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		set[line] = true
+	}
+
+	return set
+}
+
+// Merges template records by name: a record only one side changed takes that side's version
+func mergeTemplateRecords(base, ours, theirs []TemplateRecord) []TemplateRecord {
+	find := func(records []TemplateRecord, name string) (TemplateRecord, bool) {
+		for _, record := range records {
+			if record.Name == name {
+				return record, true
+			}
+		}
+
+		return TemplateRecord{}, false
+	}
+
+	merged := make([]TemplateRecord, 0, len(ours))
+
+	for _, record := range ours {
+		baseRecord, inBase := find(base, record.Name)
+		theirRecord, inTheirs := find(theirs, record.Name)
+
+		switch {
+		case inBase && !inTheirs:
+			continue // removed by theirs
+		case inBase && inTheirs && baseRecord.Hash == record.Hash:
+			merged = append(merged, theirRecord)
+		default:
+			merged = append(merged, record)
+		}
+	}
+
+	for _, record := range theirs {
+		_, inBase := find(base, record.Name)
+		_, inOurs := find(ours, record.Name)
+
+		if !inBase && !inOurs {
+			merged = append(merged, record)
+		}
+	}
+
+	return merged
+}
+
+// MARK: Rule metadata
+
+// Returns the metadata of every managed rule, by rule and then by copy
+func metaByRule(f IgnoreFile) map[string][]ruleMeta {
+	metas := make(map[string][]ruleMeta, len(f.rules))
+	for idx, rule := range f.rules {
+		key := ruleKey(rule)
+		metas[key] = append(metas[key], f.metaAt(idx))
+	}
+
+	return metas
+}
+
+// Returns the metadata of the nth copy of a rule
+func nthMeta(metas map[string][]ruleMeta, key string, n int) (ruleMeta, bool) {
+	if n >= len(metas[key]) {
+		return ruleMeta{}, false
+	}
+
+	return metas[key][n], true
+}
+
+// Merges the section and comments of a rule both sides have. Reports whether the section and the
+// comments merged cleanly; a conflicting section keeps the section of ours
+func mergeRuleMeta(base, ours, theirs ruleMeta) (ruleMeta, bool, bool) {
+	merged := ours
+
+	sectionClean := true
+	switch {
+	case ours.section == theirs.section, theirs.section == base.section:
+	case ours.section == base.section:
+		merged.section = theirs.section
+	default:
+		sectionClean = false
+	}
+
+	comments, commentsClean := mergeLines(base.comments, ours.comments, theirs.comments)
+	detached, detachedClean := mergeLines(base.detached, ours.detached, theirs.detached)
+	merged.comments, merged.detached = comments, detached
+
+	return merged, sectionClean, commentsClean && detachedClean
+}
+
+// MARK: Lines
+
+// Markers git writes around the two versions of a conflicting change
+const (
+	conflictMarkerOurs      = "<<<<<<< ours"
+	conflictMarkerSeparator = "======="
+	conflictMarkerTheirs    = ">>>>>>> theirs"
+)
+
+// Merges two versions of a list of lines three-way, the way diff3 does: lines both sides kept
+// from the base split the lists into chunks, and a chunk changed on one side only takes that
+// side's version. A chunk both sides changed differently is written between conflict markers.
+// Reports whether every chunk merged cleanly
+func mergeLines(base, ours, theirs []string) ([]string, bool) {
+	oursMatches := matchLines(base, ours)
+	theirsMatches := matchLines(base, theirs)
+
+	merged := make([]string, 0, len(ours))
+	clean := true
+
+	i, j, k := 0, 0, 0
+	for {
+		// The next base line both sides kept
+		next := i
+		for next < len(base) && (oursMatches[next] == -1 || theirsMatches[next] == -1) {
+			next++
+		}
+
+		oursEnd, theirsEnd := len(ours), len(theirs)
+		if next < len(base) {
+			oursEnd, theirsEnd = oursMatches[next], theirsMatches[next]
+		}
+
+		chunk, ok := mergeChunk(base[i:next], ours[j:oursEnd], theirs[k:theirsEnd])
+		merged = append(merged, chunk...)
+		clean = clean && ok
+
+		if next == len(base) {
+			break
+		}
+
+		merged = append(merged, base[next])
+		i, j, k = next+1, oursEnd+1, theirsEnd+1
+	}
+
+	return merged, clean
+}
+
+func mergeChunk(base, ours, theirs []string) ([]string, bool) {
+	switch {
+	case slices.Equal(ours, theirs), slices.Equal(theirs, base):
+		return ours, true
+	case slices.Equal(ours, base):
+		return theirs, true
+	}
+
+	conflict := append([]string{conflictMarkerOurs}, ours...)
+	conflict = append(conflict, conflictMarkerSeparator)
+	conflict = append(conflict, theirs...)
+
+	return append(conflict, conflictMarkerTheirs), false
+}
+
+// Returns, for each line of a, the index of the line of b it is matched with in a longest common
+// subsequence of both, or -1 if it is not part of it
+func matchLines(a, b []string) []int {
+	// lengths[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	matches := make([]int, len(a))
+	for i, j := 0, 0; i < len(a); {
+		switch {
+		case j < len(b) && a[i] == b[j]:
+			matches[i] = j
+			i++
+			j++
+		case j < len(b) && lengths[i][j+1] >= lengths[i+1][j]:
+			j++
+		default:
+			matches[i] = -1
+			i++
+		}
+	}
+
+	return matches
+}
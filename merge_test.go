@@ -0,0 +1,210 @@
+package gignore
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name           string
+		base           string
+		ours           string
+		theirs         string
+		expected       string
+		conflicts      []string
+		mergeConflicts []string
+	}{
+		{
+			name:     "Pass-BothAppend",
+			base:     "*.log\n",
+			ours:     "*.log\nbuild/\n",
+			theirs:   "*.log\n*.tmp\n",
+			expected: "*.log\nbuild/\n*.tmp",
+		},
+		{
+			name:     "Pass-SameAdditionKeptOnce",
+			base:     "*.log\n",
+			ours:     "*.log\nbuild/\n",
+			theirs:   "*.log\nbuild/\n",
+			expected: "*.log\nbuild/",
+		},
+		{
+			name:     "Pass-DeletionRespected",
+			base:     "*.log\nbuild/\n*.tmp\n",
+			ours:     "*.log\nbuild/\n*.tmp\n.env\n",
+			theirs:   "*.log\n*.tmp\n",
+			expected: "*.log\n*.tmp\n.env",
+		},
+		{
+			name:     "Pass-DeletedOnBothSides",
+			base:     "*.log\nbuild/\n",
+			ours:     "*.log\n",
+			theirs:   "*.log\n",
+			expected: "*.log",
+		},
+		{
+			name:     "Pass-AdditionPlacedAfterNeighbour",
+			base:     "*.log\nbuild/\n",
+			ours:     "*.log\nbuild/\n.env\n",
+			theirs:   "*.log\n*.tmp\nbuild/\n",
+			expected: "*.log\n*.tmp\nbuild/\n.env",
+		},
+		{
+			name:     "Pass-SectionsFollowTheirSide",
+			base:     "# Logs\n*.log\n",
+			ours:     "# Logs\n*.log\n\n# Build\nbuild/\n",
+			theirs:   "# Logs\n*.log\n\n# Editors\n.idea/\n",
+			expected: "# Logs\n*.log\n\n# Build\nbuild/\n\n# Editors\n.idea/",
+		},
+		{
+			name:     "Pass-NegationAcrossSides",
+			base:     "build/\n",
+			ours:     "build/\n*.log\n",
+			theirs:   "build/\n!debug.log\n",
+			expected: "build/\n*.log\n!debug.log",
+		},
+		{
+			name:      "Pass-ConflictReported",
+			base:      "build/\n",
+			ours:      "build/\n!debug.log\n",
+			theirs:    "build/\n*.log\n",
+			expected:  "build/\n!debug.log\n*.log",
+			conflicts: []string{"INEFFECTIVE_RULE: Rules '!debug.log' and '*.log'"},
+		},
+		{
+			name:     "Pass-ExistingConflictNotReported",
+			base:     "build/\n*.log\n!*.log\n",
+			ours:     "build/\n*.log\n!*.log\n",
+			theirs:   "build/\n*.log\n!*.log\n.idea/\n",
+			expected: "build/\n*.log\n!*.log\n.idea/",
+		},
+		{
+			name:     "Pass-CommentAddedByTheirs",
+			base:     "*.log\nbuild/\n",
+			ours:     "*.log\nbuild/\n.env\n",
+			theirs:   "*.log\n# Output of make\nbuild/\n",
+			expected: "*.log\n# Output of make\nbuild/\n.env",
+		},
+		{
+			name:     "Pass-SectionAddedByTheirs",
+			base:     "*.log\n\nbuild/\n",
+			ours:     "*.log\n.env\n\nbuild/\n",
+			theirs:   "*.log\n\n# Build\nbuild/\n",
+			expected: "*.log\n.env\n\n# Build\nbuild/",
+		},
+		{
+			name:     "Pass-FooterAddedByTheirs",
+			base:     "*.log\nbuild/\n",
+			ours:     "*.log\nbuild/\n.env\n",
+			theirs:   "*.log\nbuild/\n\n# Keep this file sorted\n",
+			expected: "*.log\nbuild/\n.env\n\n# Keep this file sorted",
+		},
+		{
+			name:     "Pass-DirectiveRemovedByTheirs",
+			base:     "# gignore:disable REDUNDANT_RULE\n\n*.log\n",
+			ours:     "# gignore:disable REDUNDANT_RULE\n\n*.log\nbuild/\n",
+			theirs:   "*.log\n",
+			expected: "*.log\nbuild/",
+		},
+		{
+			name:           "Fail-CommentsChangedOnBothSides",
+			base:           "*.log\nbuild/\n",
+			ours:           "*.log\n# Output of make\nbuild/\n",
+			theirs:         "*.log\n# Output of go build\nbuild/\n",
+			expected:       "*.log\n<<<<<<< ours\n# Output of make\n=======\n# Output of go build\n>>>>>>> theirs\nbuild/",
+			mergeConflicts: []string{"MERGE_CONFLICT: comments of 'build/' changed on both sides"},
+		},
+		{
+			name:     "Pass-LineAddedOutsideManagedBlockByTheirs",
+			base:     "local.txt\n# BEGIN gignore:managed\n*.log\n# END gignore:managed\n",
+			ours:     "local.txt\n# BEGIN gignore:managed\n*.log\nbuild/\n# END gignore:managed\n",
+			theirs:   "local.txt\n# BEGIN gignore:managed\n*.log\n# END gignore:managed\nsecrets.txt\n",
+			expected: "local.txt\n# BEGIN gignore:managed\n*.log\nbuild/\n# END gignore:managed\nsecrets.txt",
+		},
+		{
+			name:     "Pass-LinesOutsideManagedBlockChangedOnBothSides",
+			base:     "a.txt\nb.txt\n# BEGIN gignore:managed\n*.log\n# END gignore:managed\n",
+			ours:     "ours.txt\na.txt\nb.txt\n# BEGIN gignore:managed\n*.log\n# END gignore:managed\n",
+			theirs:   "a.txt\nb.txt\ntheirs.txt\n# BEGIN gignore:managed\n*.log\n# END gignore:managed\n",
+			expected: "ours.txt\na.txt\nb.txt\ntheirs.txt\n# BEGIN gignore:managed\n*.log\n# END gignore:managed",
+		},
+		{
+			name:           "Fail-LinesOutsideManagedBlockConflict",
+			base:           "local.txt\n# BEGIN gignore:managed\n*.log\n# END gignore:managed\n",
+			ours:           "ours.txt\n# BEGIN gignore:managed\n*.log\n# END gignore:managed\n",
+			theirs:         "theirs.txt\n# BEGIN gignore:managed\n*.log\n# END gignore:managed\n",
+			expected:       "<<<<<<< ours\nours.txt\n=======\ntheirs.txt\n>>>>>>> theirs\n# BEGIN gignore:managed\n*.log\n# END gignore:managed",
+			mergeConflicts: []string{"MERGE_CONFLICT: lines before the managed block changed on both sides"},
+		},
+		{
+			name:           "Fail-ManagedBlockRemovedByTheirs",
+			base:           "local.txt\n# BEGIN gignore:managed\n*.log\n# END gignore:managed\n",
+			ours:           "local.txt\n# BEGIN gignore:managed\n*.log\n# END gignore:managed\n",
+			theirs:         "local.txt\n*.log\n",
+			expected:       "local.txt\n# BEGIN gignore:managed\n*.log\n# END gignore:managed",
+			mergeConflicts: []string{"MERGE_CONFLICT: managed block changed on both sides"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var base, ours, theirs IgnoreFile
+			checkErrors("", Parse(tc.base, &base), t)
+			checkErrors("", Parse(tc.ours, &ours), t)
+			checkErrors("", Parse(tc.theirs, &theirs), t)
+
+			merged, conflicts, mergeConflicts := Merge(base, ours, theirs)
+
+			if rendered := Render(&merged, RenderOptions{}); rendered != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, rendered)
+			}
+
+			var logs []string
+			for _, conflict := range conflicts {
+				logs = append(logs, conflict.Log())
+			}
+
+			if !reflect.DeepEqual(logs, tc.conflicts) {
+				t.Errorf("expected conflicts %v, got %v", tc.conflicts, logs)
+			}
+
+			var mergeLogs []string
+			for _, conflict := range mergeConflicts {
+				mergeLogs = append(mergeLogs, conflict.Log())
+			}
+
+			if !reflect.DeepEqual(mergeLogs, tc.mergeConflicts) {
+				t.Errorf("expected merge conflicts %v, got %v", tc.mergeConflicts, mergeLogs)
+			}
+		})
+	}
+}
+
+func TestMergeTemplateRecords(t *testing.T) {
+	v1 := TemplateRecord{Name: "go", Hash: "v1"}
+	v2 := TemplateRecord{Name: "go", Hash: "v2"}
+	node := TemplateRecord{Name: "node", Hash: "v1"}
+
+	tests := []struct {
+		name     string
+		base     []TemplateRecord
+		ours     []TemplateRecord
+		theirs   []TemplateRecord
+		expected []TemplateRecord
+	}{
+		{name: "Pass-Unchanged", base: []TemplateRecord{v1}, ours: []TemplateRecord{v1}, theirs: []TemplateRecord{v1}, expected: []TemplateRecord{v1}},
+		{name: "Pass-UpgradedByTheirs", base: []TemplateRecord{v1}, ours: []TemplateRecord{v1}, theirs: []TemplateRecord{v2}, expected: []TemplateRecord{v2}},
+		{name: "Pass-UpgradedByOurs", base: []TemplateRecord{v1}, ours: []TemplateRecord{v2}, theirs: []TemplateRecord{v1}, expected: []TemplateRecord{v2}},
+		{name: "Pass-AddedByTheirs", base: []TemplateRecord{v1}, ours: []TemplateRecord{v1}, theirs: []TemplateRecord{v1, node}, expected: []TemplateRecord{v1, node}},
+		{name: "Pass-RemovedByTheirs", base: []TemplateRecord{v1, node}, ours: []TemplateRecord{v1, node}, theirs: []TemplateRecord{v1}, expected: []TemplateRecord{v1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if merged := mergeTemplateRecords(tc.base, tc.ours, tc.theirs); !reflect.DeepEqual(merged, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, merged)
+			}
+		})
+	}
+}
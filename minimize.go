@@ -0,0 +1,275 @@
+package gignore
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Minimize rewrites ignore files into a smaller rule set that ignores exactly the same paths.
+// Unlike conflict detection, which flags rules that look redundant, every rewrite here is only
+// made when it provably preserves matching behavior, so some candidates are left alone.
+//
+// A rule is covered by another rule if the other rule matches every path it matches. Covered
+// rules are dropped when the covering rule comes later (the covered rule can then never be the
+// last match), or when it comes earlier and every rule in between has the same action.
+
+// MARK: Coverage
+
+const globMetaCharacters = `*?[]\`
+
+func isLiteralPattern(pattern string) bool {
+	return !strings.ContainsAny(pattern, globMetaCharacters)
+}
+
+// Returns the pattern relative to the ignore file's directory if it is anchored, i.e. it contains
+// a "/" other than a trailing one
+func anchoredPattern(rule Ruler) (string, bool) {
+	pattern := rule.Pattern()
+	if !strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		return "", false
+	}
+
+	return strings.TrimPrefix(pattern, "/"), true
+}
+
+// Reports whether broad matches every path specific matches. Only cases that can be decided
+// from the patterns alone are recognized, anything else is reported as not covered
+func covers(broad, specific Ruler) bool {
+	if broad.Pattern() == specific.Pattern() {
+		return true
+	}
+
+	switch b := broad.(type) {
+	case ExtensionRule:
+		// "*.log" matches every name that ends in ".log", at any depth
+		if !isLiteralPattern(b.ext) {
+			return false
+		}
+
+		name := path.Base(strings.TrimSuffix(specific.Pattern(), "/"))
+		return strings.HasSuffix(name, "."+b.ext)
+
+	case DirectoryRule:
+		if !isLiteralPattern(b.name) {
+			return false
+		}
+
+		switch b.mode {
+		case RECURSIVE:
+			// "logs/**" matches everything below logs
+			anchored, ok := anchoredPattern(specific)
+			return ok && strings.HasPrefix(anchored, b.name+"/")
+
+		case CHILDREN:
+			// "logs/*" matches every name directly below logs
+			anchored, ok := anchoredPattern(specific)
+			if !ok {
+				return false
+			}
+
+			name, found := strings.CutPrefix(strings.TrimSuffix(anchored, "/"), b.name+"/")
+			return found && name != "" && !strings.Contains(name, "/") && !strings.Contains(name, "**")
+
+		case ANYWHERE:
+			// "**/name" matches name at any depth
+			file, ok := specific.(FileRule)
+			return ok && isLiteralPattern(file.path) && path.Base(file.path) == b.name
+		}
+	}
+
+	return false
+}
+
+// Returns the index of the rule that makes the rule at idx unnecessary: a rule that decides every
+// path the rule at idx matches, or that decides it the same way once the rule is gone. Returns -1
+// if the rule at idx is not covered
+func (f IgnoreFile) coveringRule(idx int) int {
+	rule := f.rules[idx]
+
+	// A later rule matching the same paths always wins, whatever its action
+	for i := idx + 1; i < len(f.rules); i++ {
+		if covers(f.rules[i], rule) {
+			return i
+		}
+	}
+
+	// An earlier rule decides the same way once the rule is gone, unless a rule in between flips it
+	for i := idx - 1; i >= 0; i-- {
+		earlier := f.rules[i]
+		if earlier.Action() != rule.Action() {
+			return -1
+		}
+
+		if covers(earlier, rule) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// MARK: Extension merging
+
+// Returns the part of an extension that extensions merged into a character class share, e.g.
+// "py" for "pyc", if the extension can be merged
+func mergeableExtension(rule Ruler) (string, rune, bool) {
+	ext, ok := rule.(ExtensionRule)
+	if !ok || !isLiteralPattern(ext.ext) {
+		return "", 0, false
+	}
+
+	runes := []rune(ext.ext)
+	if len(runes) < 2 {
+		return "", 0, false
+	}
+
+	last := runes[len(runes)-1]
+	if !unicode.IsLetter(last) && !unicode.IsDigit(last) {
+		return "", 0, false
+	}
+
+	return string(runes[:len(runes)-1]), last, true
+}
+
+// Returns the indexes of the extension rules that can merge with the rule at idx: rules with the
+// same action, section and extension but for the last character, up to the first rule with the
+// opposite action
+func (f IgnoreFile) mergeCandidates(idx int) []int {
+	rule := f.rules[idx]
+	prefix, last, ok := mergeableExtension(rule)
+	if !ok {
+		return nil
+	}
+
+	candidates := []int{idx}
+	seen := map[rune]bool{last: true}
+
+	for i := idx + 1; i < len(f.rules); i++ {
+		other := f.rules[i]
+		if other.Action() != rule.Action() {
+			break
+		}
+
+		otherPrefix, otherLast, ok := mergeableExtension(other)
+		if !ok || otherPrefix != prefix || seen[otherLast] || f.sectionAt(i) != f.sectionAt(idx) {
+			continue
+		}
+
+		seen[otherLast] = true
+		candidates = append(candidates, i)
+	}
+
+	return candidates
+}
+
+// Replaces the extension rules at indexes with one rule matching the same names, e.g. "*.pyc"
+// and "*.pyo" with "*.py[co]", at the position of the first one. The comments above every merged
+// rule move onto the new rule
+func (f *IgnoreFile) mergeExtensions(indexes []int) ([]Result, error) {
+	first := f.rules[indexes[0]]
+	prefix, _, _ := mergeableExtension(first)
+
+	results := make([]Result, 0, len(indexes)+1)
+	lasts := make([]rune, 0, len(indexes))
+
+	for _, idx := range indexes {
+		rule := f.rules[idx]
+		_, last, _ := mergeableExtension(rule)
+
+		lasts = append(lasts, last)
+		results = append(results, Result{Rule: rule, Result: REMOVED, Reason: MINIMIZED, Position: f.managedPosition(idx)})
+	}
+
+	sort.Slice(lasts, func(i, j int) bool { return lasts[i] < lasts[j] })
+
+	merged, err := NewExtensionRule(prefix+"["+string(lasts)+"]", first.Action())
+	if err != nil {
+		return nil, err
+	}
+
+	// Every removal shifts the rules after it up by one
+	for removed, idx := range indexes[1:] {
+		f.removeKeepingComments(idx-removed, indexes[0])
+	}
+
+	meta := f.metaAt(indexes[0])
+	meta.line = 0 // the merged rule was not parsed
+	f.rules[indexes[0]] = merged
+	f.setMetaAt(indexes[0], meta)
+
+	return append(results, Result{Rule: merged, Result: ADDED, Reason: MINIMIZED}), nil
+}
+
+// MARK: Minimize
+
+// Minimize rewrites the IgnoreFile into a smaller rule set that ignores exactly the same paths:
+//   - Rules covered by another rule are removed, for example "logs/*.log" when "logs/**" is
+//     present, "debug.log" when "*.log" comes later, or "cache/*" when "cache/**" comes earlier
+//     with no negation in between
+//   - Extension rules that differ only in their last character are merged into one rule with a
+//     character class, for example "*.pyc", "*.pyo" and "*.pyd" into "*.py[cdo]", as long as no
+//     negation separates them
+//
+// Rules are only considered covered when that follows from their patterns alone, so the result is
+// not necessarily the smallest possible file. Rules outside of a managed block are not changed.
+// Comments are never dropped: the comments above a removed rule move onto the rule that covers
+// it, and the comments above merged rules onto the merged rule.
+//
+// Returns a slice of Result describing every change with reason MINIMIZED: a REMOVED result for
+// each rule dropped or merged, and an ADDED result for each merged rule. Returns an error if a
+// merged rule cannot be created, in which case the IgnoreFile is not modified.
+//
+// Example:
+//
+//	results, err := ignoreFile.Minimize()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, result := range results {
+//	    fmt.Println(result.Log()) // REMOVED: Rule 'logs/*.log', Reason: MINIMIZED
+//	}
+func (f *IgnoreFile) Minimize() ([]Result, error) {
+	working := f.clone()
+	results := make([]Result, 0)
+
+	for changed := true; changed; {
+		changed = false
+
+		for idx := 0; idx < len(working.rules); {
+			covering := working.coveringRule(idx)
+			if covering == -1 {
+				idx++
+				continue
+			}
+
+			rule := working.rules[idx]
+			position := working.managedPosition(idx)
+			working.removeKeepingComments(idx, covering)
+
+			results = append(results, Result{Rule: rule, Result: REMOVED, Reason: MINIMIZED, Position: position})
+			changed = true
+		}
+
+		for idx := 0; idx < len(working.rules); idx++ {
+			candidates := working.mergeCandidates(idx)
+			if len(candidates) < 2 {
+				continue
+			}
+
+			merged, err := working.mergeExtensions(candidates)
+			if err != nil {
+				return make([]Result, 0), err
+			}
+
+			results = append(results, merged...)
+			changed = true
+		}
+	}
+
+	*f = working
+
+	return results, nil
+}
@@ -0,0 +1,177 @@
+package gignore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCovers(t *testing.T) {
+	tests := []struct {
+		name     string
+		broad    string
+		specific string
+		expected bool
+	}{
+		{name: "Pass-Same", broad: "*.log", specific: "*.log", expected: true},
+		{name: "Pass-ExtensionCoversFile", broad: "*.log", specific: "debug.log", expected: true},
+		{name: "Pass-ExtensionCoversNestedGlob", broad: "*.log", specific: "logs/*.log", expected: true},
+		{name: "Pass-ExtensionCoversLongerExtension", broad: "*.gz", specific: "*.tar.gz", expected: true},
+		{name: "Pass-ExtensionDoesNotCoverSuffix", broad: "*.log", specific: "*.log.1", expected: false},
+		{name: "Pass-RecursiveCoversGlob", broad: "logs/**", specific: "logs/*.log", expected: true},
+		{name: "Pass-RecursiveCoversNestedDirectory", broad: "logs/**", specific: "logs/archive/", expected: true},
+		{name: "Pass-RecursiveDoesNotCoverDirectory", broad: "logs/**", specific: "logs/", expected: false},
+		{name: "Pass-RecursiveDoesNotCoverSiblingPrefix", broad: "logs/**", specific: "logsarchive/x.log", expected: false},
+		{name: "Pass-RecursiveDoesNotCoverUnanchored", broad: "logs/**", specific: "*.log", expected: false},
+		{name: "Pass-ChildrenCoversChild", broad: "cache/*", specific: "cache/index.db", expected: true},
+		{name: "Pass-ChildrenDoesNotCoverGrandchild", broad: "cache/*", specific: "cache/a/index.db", expected: false},
+		{name: "Pass-ChildrenDoesNotCoverRecursive", broad: "cache/*", specific: "cache/**", expected: false},
+		{name: "Pass-AnywhereCoversFile", broad: "**/.env", specific: "config/.env", expected: true},
+		{name: "Pass-FileCoversNothing", broad: "debug.log", specific: "logs/debug.log", expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			broad, err := parseRule(tc.broad)
+			checkErrors("", err, t)
+			specific, err := parseRule(tc.specific)
+			checkErrors("", err, t)
+
+			if result := covers(broad, specific); result != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	paths := []string{
+		"debug.log", "debug.log.1", "logs/app.log", "logs/debug.log", "logs/keep.log", "logs/archive/", "logs/archive/old.log",
+		"logsarchive/x.log", "cache/", "cache/index.db", "cache/a/index.db", "src/main.pyc", "main.pyo",
+		"main.pyd", "main.py", "keep.pyc", "build/", "build/out.bin", "config/.env", ".env",
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		results  []string
+	}{
+		{
+			name:     "Pass-Minimal",
+			content:  "*.log\nbuild/",
+			expected: "*.log\nbuild/",
+			results:  []string{},
+		},
+		{
+			name:     "Pass-CoveredByRecursiveDirectory",
+			content:  "logs/**\n*.log.1\nlogs/*.log",
+			expected: "logs/**\n*.log.1",
+			results:  []string{"REMOVED: Rule 'logs/*.log', Reason: MINIMIZED"},
+		},
+		{
+			name:     "Pass-CoveredByLaterRule",
+			content:  "debug.log\n!logs/keep.log\n*.log",
+			expected: "*.log",
+			results: []string{
+				"REMOVED: Rule 'debug.log', Reason: MINIMIZED",
+				"REMOVED: Rule '!logs/keep.log', Reason: MINIMIZED",
+			},
+		},
+		{
+			name:     "Pass-KeepsNegation",
+			content:  "*.log\n!logs/keep.log",
+			expected: "*.log\n!logs/keep.log",
+			results:  []string{},
+		},
+		{
+			name:     "Pass-NegationOverriddenLater",
+			content:  "*.log\n!logs/keep.log\nlogs/keep.log",
+			expected: "*.log",
+			results: []string{
+				"REMOVED: Rule '!logs/keep.log', Reason: MINIMIZED",
+				"REMOVED: Rule 'logs/keep.log', Reason: MINIMIZED",
+			},
+		},
+		{
+			name:     "Pass-NegationBlocksEarlierRule",
+			content:  "*.log\n!debug.log\nlogs/*.log",
+			expected: "*.log\n!debug.log\nlogs/*.log",
+			results:  []string{},
+		},
+		{
+			name:     "Pass-MergeExtensions",
+			content:  "*.pyc\nbuild/\n*.pyo\n*.pyd",
+			expected: "*.py[cdo]\nbuild/",
+			results: []string{
+				"REMOVED: Rule '*.pyc', Reason: MINIMIZED",
+				"REMOVED: Rule '*.pyo', Reason: MINIMIZED",
+				"REMOVED: Rule '*.pyd', Reason: MINIMIZED",
+				"ADDED: Rule '*.py[cdo]', Reason: MINIMIZED",
+			},
+		},
+		{
+			name:     "Pass-NoMergeAcrossNegation",
+			content:  "*.pyc\n!keep.pyc\n*.pyo",
+			expected: "*.pyc\n!keep.pyc\n*.pyo",
+			results:  []string{},
+		},
+		{
+			name:     "Pass-CommentsFollowMergedRule",
+			content:  "# Python\n*.pyc\n# Optimized\n*.pyo",
+			expected: "# Python\n# Optimized\n*.py[co]",
+			results: []string{
+				"REMOVED: Rule '*.pyc', Reason: MINIMIZED",
+				"REMOVED: Rule '*.pyo', Reason: MINIMIZED",
+				"ADDED: Rule '*.py[co]', Reason: MINIMIZED",
+			},
+		},
+		{
+			name:     "Pass-CommentsFollowCoveringRule",
+			content:  "*.tmp\n# Verbose output\ndebug.log\n*.log",
+			expected: "*.tmp\n# Verbose output\n*.log",
+			results:  []string{"REMOVED: Rule 'debug.log', Reason: MINIMIZED"},
+		},
+		{
+			name:     "Pass-DirectiveFollowsEarlierCoveringRule",
+			content:  "logs/**\n# gignore:ignore-next-line UNREACHABLE_RULE\nlogs/*.log",
+			expected: "# gignore:ignore-next-line UNREACHABLE_RULE\nlogs/**",
+			results:  []string{"REMOVED: Rule 'logs/*.log', Reason: MINIMIZED"},
+		},
+		{
+			name:     "Pass-DetachedCommentsStayInPlace",
+			content:  "*.tmp\n\n# Kept for reference\n\ndebug.log\nbuild/\n*.log",
+			expected: "*.tmp\n\n# Kept for reference\n\nbuild/\n*.log",
+			results:  []string{"REMOVED: Rule 'debug.log', Reason: MINIMIZED"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignoreFile := NewIgnoreFile()
+			checkErrors("", Parse(tc.content, &ignoreFile), t)
+			original := ignoreFile.clone()
+
+			results, err := ignoreFile.Minimize()
+			checkErrors("", err, t)
+
+			logs := make([]string, 0, len(results))
+			for _, result := range results {
+				logs = append(logs, result.Log())
+			}
+
+			if strings.Join(logs, "\n") != strings.Join(tc.results, "\n") {
+				t.Errorf("expected results %v, got %v", tc.results, logs)
+			}
+
+			if rendered := Render(&ignoreFile, RenderOptions{}); rendered != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, rendered)
+			}
+
+			for _, path := range paths {
+				if original.IsIgnored(path) != ignoreFile.IsIgnored(path) {
+					t.Errorf("expected %s to be ignored the same way after minimizing", path)
+				}
+			}
+		})
+	}
+}
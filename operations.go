@@ -0,0 +1,200 @@
+package gignore
+
+import "fmt"
+
+// Operation is a single modification to an IgnoreFile that can be applied as part of a batch.
+// Operations report their outcome as a slice of Result so additions, deletions and moves
+// can be combined into a single log.
+type Operation func(*IgnoreFile) ([]Result, error)
+
+// MARK: Add operations
+
+// AddFileOperation returns an Operation that adds a file rule using IgnoreFile.AddFile.
+func AddFileOperation(path string, action Action) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		return f.AddFile(path, action)
+	}
+}
+
+// AddExtensionOperation returns an Operation that adds an extension rule using IgnoreFile.AddExtension.
+func AddExtensionOperation(ext string, action Action) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		return f.AddExtension(ext, action)
+	}
+}
+
+// AddDirectoryOperation returns an Operation that adds a directory rule using IgnoreFile.AddDirectory.
+func AddDirectoryOperation(name string, mode DirectoryMode, action Action) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		return f.AddDirectory(name, mode, action)
+	}
+}
+
+// AddGlobOperation returns an Operation that adds a glob rule using IgnoreFile.AddGlob.
+func AddGlobOperation(pattern string, action Action) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		return f.AddGlob(pattern, action)
+	}
+}
+
+// MARK: Delete operations
+
+// DeleteFileOperation returns an Operation that removes a file rule using IgnoreFile.DeleteFile.
+func DeleteFileOperation(path string, action Action) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		return single(f.DeleteFile(path, action))
+	}
+}
+
+// DeleteExtensionOperation returns an Operation that removes an extension rule using IgnoreFile.DeleteExtension.
+func DeleteExtensionOperation(ext string, action Action) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		return single(f.DeleteExtension(ext, action))
+	}
+}
+
+// DeleteDirectoryOperation returns an Operation that removes a directory rule using IgnoreFile.DeleteDirectory.
+func DeleteDirectoryOperation(name string, mode DirectoryMode, action Action) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		return single(f.DeleteDirectory(name, mode, action))
+	}
+}
+
+// DeleteGlobOperation returns an Operation that removes a glob rule using IgnoreFile.DeleteGlob.
+func DeleteGlobOperation(pattern string, action Action) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		return single(f.DeleteGlob(pattern, action))
+	}
+}
+
+// MARK: Move operations
+
+// MoveRuleOperation returns an Operation that relocates a rule relative to another rule.
+// Both patterns are parsed the same way as Service.MoveRule.
+func MoveRuleOperation(rulePattern, targetRulePattern string, direction MoveDirection) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		ruleToMove, err := parseRule(rulePattern)
+		if err != nil {
+			return nil, err
+		}
+
+		targetRule, err := parseRule(targetRulePattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return single(f.MoveRule(ruleToMove, targetRule, direction, REQUESTED))
+	}
+}
+
+// MARK: Section operations
+
+// AddToSectionOperation returns an Operation that parses rulePattern and adds the rule to a
+// named section using IgnoreFile.AddToSection.
+func AddToSectionOperation(section, rulePattern string) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		rule, err := parseRule(rulePattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return f.AddToSection(section, rule)
+	}
+}
+
+// MoveToSectionOperation returns an Operation that parses rulePattern and moves the rule to a
+// named section using IgnoreFile.MoveRuleToSection.
+func MoveToSectionOperation(rulePattern, section string) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		rule, err := parseRule(rulePattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return single(f.MoveRuleToSection(rule, section, REQUESTED))
+	}
+}
+
+// Wraps single-result operations so they can be combined with multi-result ones
+func single(result Result, err error) ([]Result, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	// MoveRule returns an empty result when no move was needed
+	if result == (Result{}) {
+		return make([]Result, 0), nil
+	}
+
+	return []Result{result}, nil
+}
+
+// MARK: Ensure operations
+
+// EnsurePresentOperation returns an Operation that parses rulePattern and makes sure the
+// rule exists using IgnoreFile.EnsurePresent.
+func EnsurePresentOperation(rulePattern string) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		rule, err := parseRule(rulePattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return f.EnsurePresent(rule)
+	}
+}
+
+// EnsureAbsentOperation returns an Operation that parses rulePattern and makes sure the
+// rule does not exist using IgnoreFile.EnsureAbsent.
+func EnsureAbsentOperation(rulePattern string) Operation {
+	return func(f *IgnoreFile) ([]Result, error) {
+		rule, err := parseRule(rulePattern)
+		if err != nil {
+			return nil, err
+		}
+
+		return single(f.EnsureAbsent(rule))
+	}
+}
+
+// MARK: Batch
+
+// Batch applies a sequence of operations to the IgnoreFile as a single unit.
+// The operations are applied in order against a copy of the IgnoreFile, and the copy
+// only replaces the original once every operation has succeeded.
+//
+// Parameters:
+//   - operations: The operations to apply, in order.
+//
+// Returns the combined slice of Result from every operation and an error. The error will be
+// non-nil if any operation fails, in which case the IgnoreFile is left unchanged and the
+// returned results are discarded. The error names the index of the failed operation, e.g.
+// "operation 1: rule not found", and wraps the operation's error.
+//
+// Example:
+//
+//	results, err := ignoreFile.Batch(
+//	    AddExtensionOperation("log", INCLUDE),
+//	    AddDirectoryOperation("build", RECURSIVE, INCLUDE),
+//	    AddFileOperation("build/important.txt", EXCLUDE),
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (f *IgnoreFile) Batch(operations ...Operation) ([]Result, error) {
+	working := f.clone()
+	results := make([]Result, 0)
+
+	for idx, operation := range operations {
+		opResults, err := operation(&working)
+		if err != nil {
+			return make([]Result, 0), fmt.Errorf("operation %d: %w", idx, err)
+		}
+
+		results = append(results, opResults...)
+	}
+
+	*f = working
+
+	return results, nil
+}
@@ -0,0 +1,101 @@
+package gignore
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		ignore       IgnoreFile
+		operations   []Operation
+		errorMessage string
+		resultCount  int
+		expected     []Ruler
+	}{
+		{
+			name: "Pass",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					FileRule{path: "todo.md", act: INCLUDE},
+				},
+			},
+			operations: []Operation{
+				AddExtensionOperation("log", INCLUDE),
+				AddDirectoryOperation("build", RECURSIVE, INCLUDE),
+				AddFileOperation("build/important.txt", EXCLUDE),
+				DeleteFileOperation("todo.md", INCLUDE),
+			},
+			errorMessage: "",
+			resultCount:  4,
+			expected: []Ruler{
+				ExtensionRule{ext: "log", act: INCLUDE},
+				DirectoryRule{name: "build", mode: RECURSIVE, act: INCLUDE},
+				FileRule{path: "build/important.txt", act: EXCLUDE},
+			},
+		},
+		{
+			name: "Pass-MoveAlreadyInPlace",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					DirectoryRule{name: "build", mode: RECURSIVE, act: INCLUDE},
+					FileRule{path: "build/important.txt", act: EXCLUDE},
+				},
+			},
+			operations: []Operation{
+				MoveRuleOperation("!build/important.txt", "build/**", AFTER),
+			},
+			errorMessage: "",
+			resultCount:  0,
+			expected: []Ruler{
+				DirectoryRule{name: "build", mode: RECURSIVE, act: INCLUDE},
+				FileRule{path: "build/important.txt", act: EXCLUDE},
+			},
+		},
+		{
+			name: "Fail-RollsBack",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					FileRule{path: "todo.md", act: INCLUDE},
+				},
+			},
+			operations: []Operation{
+				AddExtensionOperation("log", INCLUDE),
+				DeleteGlobOperation("temp*.txt", INCLUDE),
+			},
+			errorMessage: "operation 1: " + ruleNotFoundError.Error(),
+			resultCount:  0,
+			expected: []Ruler{
+				FileRule{path: "todo.md", act: INCLUDE},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := tc.ignore.Batch(tc.operations...)
+
+			checkErrors(tc.errorMessage, err, t)
+
+			if tc.errorMessage != "" && !errors.Is(err, ruleNotFoundError) {
+				t.Errorf("expected error to wrap %s", ruleNotFoundError)
+			}
+
+			if len(results) != tc.resultCount {
+				t.Errorf("expected %d results, got %d", tc.resultCount, len(results))
+			}
+
+			if len(tc.ignore.rules) != len(tc.expected) {
+				t.Errorf("expected %d rules, found %d", len(tc.expected), len(tc.ignore.rules))
+				return
+			}
+
+			for idx, rule := range tc.ignore.rules {
+				if !rulesEqual(tc.expected[idx], rule) {
+					t.Errorf("Expected rule %s, found %s", tc.expected[idx].Render(), rule.Render())
+				}
+			}
+		})
+	}
+}
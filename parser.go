@@ -4,6 +4,8 @@ import (
 	"errors"
 	"log"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 var invalidDirectoryError = errors.New("invalid directory error")
@@ -55,6 +57,48 @@ func isGlobPattern(line string) bool {
 		strings.Contains(line, "[")
 }
 
+// Splits content into lines at "\n", "\r\n" and "\r" line breaks after stripping a UTF-8 byte
+// order mark. Returns the lines, the style of the first line break and whether there was a mark
+func splitLines(content string) ([]string, LineEnding, bool) {
+	content, bom := strings.CutPrefix(content, byteOrderMark)
+
+	ending := LF
+	if idx := strings.IndexAny(content, "\r\n"); idx != -1 && content[idx] == '\r' {
+		ending = CR
+		if strings.HasPrefix(content[idx:], "\r\n") {
+			ending = CRLF
+		}
+	}
+
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+
+	return strings.Split(content, "\n"), ending, bom
+}
+
+// Strips leading whitespace and the trailing spaces git ignores. git only strips spaces, so
+// trailing tabs are part of the pattern, and so is a space escaped with a backslash: `foo\ `
+// keeps its last space
+func trimWhitespace(line string) string {
+	line = strings.TrimLeftFunc(line, unicode.IsSpace)
+
+	trimmed := strings.TrimRight(line, " ")
+	if trimmed == line {
+		return line
+	}
+
+	backslashes := 0
+	for i := len(trimmed) - 1; i >= 0 && trimmed[i] == '\\'; i-- {
+		backslashes++
+	}
+
+	if backslashes%2 == 1 {
+		return line[:len(trimmed)+1]
+	}
+
+	return trimmed
+}
+
 func parseRule(line string) (Ruler, error) {
 	action := INCLUDE
 	if strings.HasPrefix(line, "!") {
@@ -87,7 +131,7 @@ func parseRule(line string) (Ruler, error) {
 //   - ignoreFile: A pointer to the IgnoreFile instance to populate with the parsed rules.
 //
 // The parsing logic follows these rules:
-//   - Empty lines and lines starting with "#" (comments) are ignored
+//   - Empty lines are ignored, and lines starting with "#" are treated as comments
 //   - Lines starting with "!" are treated as EXCLUDE actions, otherwise INCLUDE
 //   - Lines matching "*.ext" (no path separators or additional wildcards) become extension rules
 //   - Lines ending with "/", "/*", "/**" or starting with "**/" or "/" become directory rules
@@ -101,8 +145,45 @@ func parseRule(line string) (Ruler, error) {
 //   - "**/dirname" → ANYWHERE mode
 //   - "/dirname" → ROOT_ONLY mode
 //
-// Returns nil on success. Parse errors for individual lines are logged but do not stop
-// processing - invalid lines are skipped and parsing continues.
+// Encoding:
+//   - Lines may be separated by "\n", "\r\n" or "\r", and the style of the first line break is
+//     recorded, see IgnoreFile.LineEnding and PRESERVE_LINE_ENDING
+//   - A leading UTF-8 byte order mark is stripped and recorded, see IgnoreFile.HasByteOrderMark
+//   - Lines that are not valid UTF-8 are logged with their line number and parsed as they are,
+//     since git matches patterns byte by byte. The linter reports them as INVALID_UTF8
+//
+// Line numbers:
+//   - The line each rule was parsed from is recorded, so matches and conflicts can point back
+//     at the source line (see Match and PositionOf). Every copy of a repeated rule keeps its own
+//     line, section and comments
+//
+// Whitespace:
+//   - Leading whitespace is stripped, and trailing spaces unless they are escaped with a
+//     backslash: `foo\ ` keeps its last space, like git. Trailing tabs are kept, git matches them
+//
+// Comments:
+//   - Comments directly above a rule stay attached to it, and move and render with the rule
+//   - Suppression directives ("# gignore:disable ..." and "# gignore:ignore-next-line ...")
+//     are kept even when they are not directly above a rule; they render at the top of the file
+//   - Comments above the first rule that are separated from it by a blank line are kept as the
+//     file's header, and comments below the last rule as its footer
+//   - Other comments separated from the next rule by a blank line stay with that rule, and
+//     render above it followed by a blank line
+//
+// Sections:
+//   - A comment that starts a block of lines (first line, or after a blank line) and is
+//     followed by rules becomes the header of a section named after the comment text
+//   - Every following rule belongs to that section until the next section header
+//
+// Managed blocks:
+//   - If the content contains a MANAGED_BLOCK_BEGIN and MANAGED_BLOCK_END marker, only the
+//     rules between the markers are loaded as the IgnoreFile's rules
+//   - Lines outside the markers are preserved verbatim for rendering, and their rules are
+//     still taken into account by conflict analysis
+//
+// Returns nil on success, or an error if the managed block markers are unbalanced.
+// Parse errors for individual lines are logged but do not stop processing - invalid
+// lines are skipped and parsing continues.
 //
 // Example:
 //
@@ -119,24 +200,156 @@ func parseRule(line string) (Ruler, error) {
 //	    log.Fatal(err)
 //	}
 func Parse(content string, ignoreFile *IgnoreFile) error {
-	lines := strings.Split(content, "\n")
+	lines, ending, bom := splitLines(content)
+	ignoreFile.lineEnding = ending
+	ignoreFile.byteOrderMark = bom
+
+	for idx, line := range lines {
+		if !utf8.ValidString(line) {
+			log.Printf("line %d is not valid UTF-8, patterns are matched byte by byte", idx+1)
+		}
+	}
+
+	begin, end, err := findManagedBlock(lines)
+	if err != nil {
+		return err
+	}
+
+	addManaged := func(rule Ruler, meta ruleMeta) {
+		ignoreFile.addRule(rule, meta)
+	}
+
+	if begin == -1 {
+		ignoreFile.templates = extractTemplateRecords(lines)
+		ignoreFile.setLooseComments(parseLines(lines, 0, addManaged))
+		return nil
+	}
 
-	for linNum, line := range lines {
-		line = strings.TrimSpace(line)
+	after := lines[end+1:]
+	if len(after) > 0 && after[len(after)-1] == "" {
+		after = after[:len(after)-1] // drop the empty line left by a trailing newline
+	}
 
-		if line == "" || strings.HasPrefix(line, "#") {
+	ignoreFile.templates = extractTemplateRecords(lines[begin+1 : end])
+	ignoreFile.setLooseComments(parseLines(lines[begin+1:end], begin+1, addManaged))
+	ignoreFile.block = newManagedBlock(lines[:begin], after, end+1)
+
+	return nil
+}
+
+// Creates a managed block from the lines around it, parsing their rules. afterOffset is the index
+// of the first line after the block in the file
+func newManagedBlock(before, after []string, afterOffset int) *managedBlock {
+	block := managedBlock{
+		before: before,
+		after:  after,
+	}
+
+	// Rules outside of the block keep their metadata for conflict analysis, but never belong to a section
+	parseLines(block.before, 0, func(rule Ruler, meta ruleMeta) {
+		meta.section = ""
+		block.beforeRules = append(block.beforeRules, rule)
+		block.beforeMeta = append(block.beforeMeta, meta)
+	})
+	parseLines(block.after, afterOffset, func(rule Ruler, meta ruleMeta) {
+		meta.section = ""
+		block.afterRules = append(block.afterRules, rule)
+		block.afterMeta = append(block.afterMeta, meta)
+	})
+
+	return &block
+}
+
+// Comments that are not directly above a rule
+type looseComments struct {
+	header     []string // comments above the first rule, "" marks a blank line between them
+	footer     []string // comments below the last rule, "" marks a blank line between them
+	directives []string // suppression directives
+}
+
+func (f *IgnoreFile) setLooseComments(loose looseComments) {
+	f.header = loose.header
+	f.footer = loose.footer
+	f.directives = loose.directives
+}
+
+// Parses each line into a rule and hands it to add along with its metadata: its line number in
+// the file, the section it belongs to and the comments above it. offset is the index of the
+// first line in the file. Returns the comments that are not above a rule, and the suppression
+// directives that are not directly above one
+func parseLines(lines []string, offset int, add func(Ruler, ruleMeta)) looseComments {
+	section := ""
+	previousBlank := true
+	added := false
+
+	var loose looseComments
+	var comments, detached []string
+	detach := func() {
+		var kept []string
+		for _, comment := range comments {
+			if isDirective(comment) {
+				loose.directives = append(loose.directives, comment)
+				continue
+			}
+			kept = append(kept, comment)
+		}
+
+		if len(kept) > 0 && len(detached) > 0 {
+			detached = append(detached, "") // blank line between blocks of comments
+		}
+		detached = append(detached, kept...)
+		comments = nil
+	}
+
+	for idx, line := range lines {
+		line = trimWhitespace(line)
+
+		if line == "" {
+			detach()
+			previousBlank = true
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			if isSectionHeader(lines, idx, previousBlank) {
+				section = sectionName(line)
+			} else {
+				comments = append(comments, line)
+			}
+
+			previousBlank = false
 			continue
 		}
 
+		previousBlank = false
+
 		rule, err := parseRule(line)
 		if err != nil {
 			// Log and ignore errors
-			log.Printf("error loading line %d, preserving %s as is. error: %v", linNum+1, line, err)
+			log.Printf("error loading line %d, preserving %s as is. error: %v", offset+idx+1, line, err)
+			detach()
 			continue
 		}
 
-		ignoreFile.addRule(rule)
+		meta := ruleMeta{line: offset + idx + 1, section: section, comments: comments}
+		if added {
+			meta.detached = detached
+		} else {
+			loose.header = detached
+		}
+
+		add(rule, meta)
+		comments, detached = nil, nil
+		added = true
 	}
 
-	return nil
+	detach()
+
+	if added {
+		loose.footer = detached
+	} else {
+		loose.header = detached
+	}
+
+	return loose
 }
@@ -1,6 +1,9 @@
 package gignore
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestParse(t *testing.T) {
 	tests := []struct {
@@ -31,6 +34,50 @@ func TestParse(t *testing.T) {
 				act: EXCLUDE,
 			},
 		},
+		{
+			name: "Pass-EscapedTrailingSpace",
+			line: "foo\\ ",
+			ignore: IgnoreFile{
+				rules: []Ruler{},
+			},
+			expected: FileRule{
+				path: "foo\\ ",
+				act:  INCLUDE,
+			},
+		},
+		{
+			name: "Pass-TrailingTab",
+			line: "tab\t",
+			ignore: IgnoreFile{
+				rules: []Ruler{},
+			},
+			expected: FileRule{
+				path: "tab\t",
+				act:  INCLUDE,
+			},
+		},
+		{
+			name: "Pass-TrailingTabAndSpaces",
+			line: "tab\t  ",
+			ignore: IgnoreFile{
+				rules: []Ruler{},
+			},
+			expected: FileRule{
+				path: "tab\t",
+				act:  INCLUDE,
+			},
+		},
+		{
+			name: "Pass-UnescapedTrailingSpace",
+			line: "foo\\\\  ",
+			ignore: IgnoreFile{
+				rules: []Ruler{},
+			},
+			expected: FileRule{
+				path: "foo\\\\",
+				act:  INCLUDE,
+			},
+		},
 		{
 			name: "Pass-DirectoryRecursive",
 			line: "build/**",
@@ -183,3 +230,71 @@ func TestParseMultiLine(t *testing.T) {
 		}
 	}
 }
+
+func TestParseEncoding(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		lineEnding    LineEnding
+		byteOrderMark bool
+		expected      []string
+	}{
+		{name: "Pass-LF", content: "*.log\nbuild/\n", lineEnding: LF, expected: []string{"*.log", "build/"}},
+		{name: "Pass-CRLF", content: "*.log\r\nbuild/\r\n", lineEnding: CRLF, expected: []string{"*.log", "build/"}},
+		{name: "Pass-CR", content: "*.log\rbuild/\r", lineEnding: CR, expected: []string{"*.log", "build/"}},
+		{name: "Pass-Mixed", content: "*.log\rbuild/\r\n*.tmp\n", lineEnding: CR, expected: []string{"*.log", "build/", "*.tmp"}},
+		{name: "Pass-ByteOrderMark", content: "\uFEFF*.log\n", lineEnding: LF, byteOrderMark: true, expected: []string{"*.log"}},
+		{name: "Pass-ByteOrderMarkOnly", content: "\uFEFF", lineEnding: LF, byteOrderMark: true, expected: []string{}},
+		{name: "Pass-InvalidUTF8", content: "caf\xe9.txt\n", lineEnding: LF, expected: []string{"caf\xe9.txt"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignoreFile := NewIgnoreFile()
+			checkErrors("", Parse(tc.content, &ignoreFile), t)
+
+			if ending := ignoreFile.LineEnding(); ending != tc.lineEnding {
+				t.Errorf("expected line ending %s, got %s", tc.lineEnding, ending)
+			}
+
+			if bom := ignoreFile.HasByteOrderMark(); bom != tc.byteOrderMark {
+				t.Errorf("expected byte order mark %t, got %t", tc.byteOrderMark, bom)
+			}
+
+			rules := ignoreFile.Rules()
+			if len(rules) != len(tc.expected) {
+				t.Fatalf("expected %d rules, got %d: %v", len(tc.expected), len(rules), rules)
+			}
+
+			for idx, rule := range rules {
+				if rule.Render() != tc.expected[idx] {
+					t.Errorf("expected rule %q, got %q", tc.expected[idx], rule.Render())
+				}
+			}
+		})
+	}
+}
+
+func TestParseLooseComments(t *testing.T) {
+	content := "# Created by some generator\n# see LICENSE\n\n*.log\n\n# Loose note\n\nbuild/\n\n# trailing note\n"
+
+	ignoreFile := NewIgnoreFile()
+	checkErrors("", Parse(content, &ignoreFile), t)
+
+	if expected := []string{"# Created by some generator", "# see LICENSE"}; !slices.Equal(ignoreFile.header, expected) {
+		t.Errorf("expected header %q, got %q", expected, ignoreFile.header)
+	}
+
+	if expected := []string{"# trailing note"}; !slices.Equal(ignoreFile.footer, expected) {
+		t.Errorf("expected footer %q, got %q", expected, ignoreFile.footer)
+	}
+
+	build := DirectoryRule{name: "build", mode: DIRECTORY, act: INCLUDE}
+	if detached := ignoreFile.metaFor(build).detached; !slices.Equal(detached, []string{"# Loose note"}) {
+		t.Errorf("expected the loose note to stay with build/, got %q", detached)
+	}
+
+	if rendered := Render(&ignoreFile, RenderOptions{TrailingNewLine: true}); rendered != content {
+		t.Errorf("expected round trip to preserve content:\n%s\ngot:\n%s", content, rendered)
+	}
+}
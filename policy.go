@@ -0,0 +1,197 @@
+package gignore
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Policies describe an ignore file as a structured document, so ignore rules can be kept in a
+// config repository as JSON, YAML or TOML and rendered into real ignore files. The document is a
+// list of rules in file order, each with the section it belongs to and the comments above it:
+//
+//	{"rules": [
+//	  {"kind": "ext", "pattern": "*.log", "section": "Logs", "comments": ["Rotated logs too"]},
+//	  {"kind": "dir", "pattern": "build", "mode": "recursive"},
+//	  {"kind": "file", "pattern": "build/keep.txt", "action": "exclude"}
+//	]}
+//
+// Policy and PolicyRule carry json, yaml and toml struct tags. JSON is read and written with
+// encoding/json; for YAML or TOML, decode into a Policy with the library of your choice, e.g.
+// gopkg.in/yaml.v3 or github.com/BurntSushi/toml, and call Policy.IgnoreFile to validate it.
+//
+// A policy only describes rules. Managed block markers, file-level directives, template records
+// and comments that are not directly above a rule are not part of it, so they are lost when a
+// file is exported and imported again.
+
+var (
+	missingRuleKindError = errors.New("missing rule kind")
+	unexpectedModeError  = errors.New("mode is only valid for dir rules")
+)
+
+// Policy is an ignore file as a structured document. See PolicyRule for the fields of a rule.
+type Policy struct {
+	Rules []PolicyRule `json:"rules" yaml:"rules" toml:"rules"`
+}
+
+// PolicyRule is a rule of a Policy. Kind, Pattern, Mode and Action are the arguments of the rule's
+// constructor: Kind is "file", "ext", "dir" or "glob", Pattern is the path, "*.ext", directory
+// name or glob, Mode is the directory mode of dir rules (default "directory"), and Action is
+// "include" (the default) or "exclude".
+type PolicyRule struct {
+	Kind    string `json:"kind" yaml:"kind" toml:"kind"`
+	Pattern string `json:"pattern" yaml:"pattern" toml:"pattern"`
+	Mode    string `json:"mode,omitempty" yaml:"mode,omitempty" toml:"mode,omitempty"`
+	Action  string `json:"action,omitempty" yaml:"action,omitempty" toml:"action,omitempty"`
+	// Section is the name of the section the rule belongs to, rendered as a "# Section" header
+	Section string `json:"section,omitempty" yaml:"section,omitempty" toml:"section,omitempty"`
+	// Comments are rendered above the rule, one "# " line per line of text
+	Comments []string `json:"comments,omitempty" yaml:"comments,omitempty" toml:"comments,omitempty"`
+}
+
+// PolicyError reports an invalid entry of a Policy with its path in the document, e.g.
+// "rules[2].mode: invalid directory mode".
+type PolicyError struct {
+	Path string
+	Err  error
+}
+
+func (e PolicyError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err.Error())
+}
+
+func (e PolicyError) Unwrap() error {
+	return e.Err
+}
+
+// MARK: Export
+
+// NewPolicy describes an IgnoreFile as a Policy. Every rule is listed in file order, including
+// rules outside of a managed block, with its section and the text of the comments above it.
+// The managed block itself is not described: Policy.IgnoreFile creates a file without one.
+//
+// Example:
+//
+//	policy := NewPolicy(ignoreFile)
+//	encoded, err := json.MarshalIndent(policy, "", "  ")
+func NewPolicy(ignoreFile IgnoreFile) Policy {
+	policy := Policy{Rules: make([]PolicyRule, 0, len(ignoreFile.rules))}
+
+	metas := ignoreFile.allMeta()
+	for idx, rule := range ignoreFile.allRules() {
+		encoded := newRuleJSON(rule, Position{})
+		meta := metas[idx]
+
+		action, _ := encoded.Action.MarshalText()
+		entry := PolicyRule{
+			Kind:    encoded.Kind,
+			Pattern: encoded.Pattern,
+			Action:  string(action),
+			Section: meta.section,
+		}
+
+		if encoded.Mode != 0 {
+			mode, _ := encoded.Mode.MarshalText()
+			entry.Mode = string(mode)
+		}
+
+		for _, comment := range meta.comments {
+			entry.Comments = append(entry.Comments, commentText(comment))
+		}
+
+		policy.Rules = append(policy.Rules, entry)
+	}
+
+	return policy
+}
+
+// MARK: Import
+
+// Creates the rule an entry describes with the validating constructors, reporting problems with
+// the path of the offending field
+func (r PolicyRule) rule(path string) (Ruler, error) {
+	var errs []error
+
+	if r.Kind == "" {
+		return nil, PolicyError{Path: path + ".kind", Err: missingRuleKindError}
+	}
+
+	action := INCLUDE
+	if r.Action != "" {
+		var err error
+		if action, err = ActionFromString(r.Action); err != nil {
+			errs = append(errs, PolicyError{Path: path + ".action", Err: err})
+		}
+	}
+
+	var mode DirectoryMode
+	switch {
+	case r.Kind == DIRECTORY_KIND && r.Mode == "":
+		mode = DIRECTORY
+	case r.Kind == DIRECTORY_KIND:
+		var err error
+		if mode, err = DirectoryModeFromString(r.Mode); err != nil {
+			errs = append(errs, PolicyError{Path: path + ".mode", Err: err})
+		}
+	case r.Mode != "":
+		errs = append(errs, PolicyError{Path: path + ".mode", Err: unexpectedModeError})
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	rule, err := newRuleOfKind(r.Kind, r.Pattern, mode, action)
+	switch {
+	case errors.Is(err, unknownRuleKindError):
+		return nil, PolicyError{Path: path + ".kind", Err: err}
+	case err != nil:
+		return nil, PolicyError{Path: path + ".pattern", Err: err}
+	}
+
+	return rule, nil
+}
+
+// IgnoreFile creates the IgnoreFile a Policy describes. Rules are created with their validating
+// constructors (NewFileRule, NewExtensionRule, NewDirectoryRule and NewGlobRule) and added in
+// document order without conflict resolution, like Parse; use FindConflicts or FixConflicts to
+// check the result.
+//
+// Returns the IgnoreFile and an error if any entry is invalid. Every invalid entry is reported
+// as a PolicyError naming its path in the document, joined into one error.
+//
+// Example:
+//
+//	var policy Policy
+//	if err := json.Unmarshal(data, &policy); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	ignoreFile, err := policy.IgnoreFile()
+//	if err != nil {
+//	    log.Fatal(err) // rules[2].mode: invalid directory mode
+//	}
+func (p Policy) IgnoreFile() (IgnoreFile, error) {
+	ignoreFile := NewIgnoreFile()
+	var errs []error
+
+	for idx, entry := range p.Rules {
+		rule, err := entry.rule(fmt.Sprintf("rules[%d]", idx))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var comments []string
+		for _, comment := range entry.Comments {
+			comments = append(comments, commentLines(comment)...)
+		}
+
+		ignoreFile.addRule(rule, ruleMeta{section: entry.Section, comments: comments})
+	}
+
+	if len(errs) > 0 {
+		return NewIgnoreFile(), errors.Join(errs...)
+	}
+
+	return ignoreFile, nil
+}
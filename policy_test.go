@@ -0,0 +1,118 @@
+package gignore
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPolicyIgnoreFile(t *testing.T) {
+	document := `{"rules": [
+		{"kind": "ext", "pattern": "*.log", "section": "Logs", "comments": ["Rotated logs too"]},
+		{"kind": "dir", "pattern": "build", "mode": "recursive", "section": "Build"},
+		{"kind": "file", "pattern": "build/keep.txt", "action": "exclude", "section": "Build"},
+		{"kind": "dir", "pattern": "node_modules"},
+		{"kind": "glob", "pattern": "tmp*", "comments": ["Scratch files\nfrom editors"]}
+	]}`
+
+	var policy Policy
+	checkErrors("", json.Unmarshal([]byte(document), &policy), t)
+
+	ignoreFile, err := policy.IgnoreFile()
+	checkErrors("", err, t)
+
+	expected := "# Logs\n# Rotated logs too\n*.log\n\n# Build\nbuild/**\n!build/keep.txt\n\nnode_modules/\n# Scratch files\n# from editors\ntmp*\n"
+	if rendered := Render(&ignoreFile, RenderOptions{TrailingNewLine: true}); rendered != expected {
+		t.Errorf("expected %q, got %q", expected, rendered)
+	}
+
+	exported := NewPolicy(ignoreFile)
+	roundTripped, err := exported.IgnoreFile()
+	checkErrors("", err, t)
+
+	if !reflect.DeepEqual(NewPolicy(roundTripped), exported) {
+		t.Errorf("expected the policy to round-trip, got %+v", NewPolicy(roundTripped))
+	}
+
+	if mode := exported.Rules[3].Mode; mode != "directory" {
+		t.Errorf("expected the default directory mode to be exported, got %q", mode)
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	ignoreFile := NewIgnoreFile()
+	checkErrors("", Parse("custom/\n# BEGIN gignore:managed\n# IDE\n.idea/\n!*.iml\n# END gignore:managed\n", &ignoreFile), t)
+
+	expected := Policy{Rules: []PolicyRule{
+		{Kind: "dir", Pattern: "custom", Mode: "directory", Action: "include"},
+		{Kind: "dir", Pattern: ".idea", Mode: "directory", Action: "include", Section: "IDE"},
+		{Kind: "ext", Pattern: "*.iml", Action: "exclude", Section: "IDE"},
+	}}
+
+	policy := NewPolicy(ignoreFile)
+	if !reflect.DeepEqual(policy, expected) {
+		t.Errorf("expected %+v, got %+v", expected, policy)
+	}
+
+	// The rules round-trip, the managed block does not
+	imported, err := policy.IgnoreFile()
+	checkErrors("", err, t)
+
+	if rendered, expected := Render(&imported, RenderOptions{}), "custom/\n\n# IDE\n.idea/\n!*.iml"; rendered != expected {
+		t.Errorf("expected %q, got %q", expected, rendered)
+	}
+}
+
+func TestPolicyErrors(t *testing.T) {
+	policy := Policy{Rules: []PolicyRule{
+		{Kind: "ext", Pattern: "log"},
+		{Kind: "ext", Pattern: "*.log", Mode: "recursive"},
+		{Kind: "dir", Pattern: "build", Mode: "sideways", Action: "maybe"},
+		{Kind: "file", Pattern: " "},
+		{Kind: "regex", Pattern: ".*"},
+		{Pattern: "todo.md"},
+	}}
+
+	_, err := policy.IgnoreFile()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	expected := []string{
+		"rules[1].mode: mode is only valid for dir rules",
+		"rules[2].action: invalid action",
+		"rules[2].mode: invalid directory mode",
+		"rules[3].pattern: path cannot be empty",
+		"rules[4].kind: unknown rule kind",
+		"rules[5].kind: missing rule kind",
+	}
+	if lines := strings.Split(err.Error(), "\n"); !reflect.DeepEqual(lines, expected) {
+		t.Errorf("expected %q, got %q", expected, lines)
+	}
+
+	var policyErr PolicyError
+	if !errors.As(err, &policyErr) || policyErr.Path != "rules[1].mode" {
+		t.Errorf("expected the first error to be a PolicyError for rules[1].mode, got %v", policyErr)
+	}
+
+	if !errors.Is(err, invalidDirectoryModeError) {
+		t.Errorf("expected the invalid directory mode error to be wrapped")
+	}
+}
+
+func TestPolicyTags(t *testing.T) {
+	for _, policyType := range []reflect.Type{reflect.TypeOf(Policy{}), reflect.TypeOf(PolicyRule{})} {
+		for idx := 0; idx < policyType.NumField(); idx++ {
+			field := policyType.Field(idx)
+			jsonTag := field.Tag.Get("json")
+
+			for _, codec := range []string{"yaml", "toml"} {
+				if tag := field.Tag.Get(codec); tag != jsonTag {
+					t.Errorf("expected %s.%s to have %s tag %q, got %q", policyType.Name(), field.Name, codec, jsonTag, tag)
+				}
+			}
+		}
+	}
+}
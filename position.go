@@ -0,0 +1,85 @@
+package gignore
+
+import "fmt"
+
+// Position is where a rule was read from, so findings can point at the exact line.
+type Position struct {
+	// Source is the ignore file the rule was loaded from, if known
+	Source string `json:"source,omitempty"`
+	// Line is the line the rule was parsed from, or 0 if the rule was not parsed
+	Line int `json:"line"`
+}
+
+// IsValid reports whether the Position refers to a line, i.e. the rule was parsed.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the Position as "source:line", or "line" if the source is unknown. Returns an
+// empty string if the Position is not valid.
+//
+// Example output: "services/api/.gitignore:42"
+func (p Position) String() string {
+	switch {
+	case !p.IsValid():
+		return ""
+	case p.Source == "":
+		return fmt.Sprintf("%d", p.Line)
+	default:
+		return fmt.Sprintf("%s:%d", p.Source, p.Line)
+	}
+}
+
+// Source returns the ignore file the IgnoreFile was loaded from, or an empty string if unknown.
+func (f IgnoreFile) Source() string {
+	return f.source
+}
+
+// SetSource records the ignore file the IgnoreFile was loaded from, which is reported in the
+// positions of its rules. FileRepository.Load and LoadIgnoreFileSet set it automatically.
+func (f *IgnoreFile) SetSource(source string) {
+	f.source = source
+}
+
+// PositionOf returns where a rule was parsed from. The Line is 0 for rules that were added after
+// parsing, and for rules the IgnoreFile does not contain. If the rule appears more than once,
+// the position of the first copy is returned; conflicts and matches carry the position of the
+// copy they are about.
+//
+// Example:
+//
+//	for _, rule := range ignoreFile.Rules() {
+//	    fmt.Printf("%s: %s\n", ignoreFile.PositionOf(rule), rule.Render()) // .gitignore:3: *.log
+//	}
+func (f IgnoreFile) PositionOf(rule Ruler) Position {
+	lines := f.allLines()
+	for idx, existing := range f.allRules() {
+		if rulesEqual(existing, rule) {
+			return Position{Source: f.source, Line: lines[idx]}
+		}
+	}
+
+	return Position{Source: f.source}
+}
+
+// Returns the position of the managed rule at idx
+func (f IgnoreFile) managedPosition(idx int) Position {
+	return Position{Source: f.source, Line: f.lineAt(idx)}
+}
+
+// Returns the line the managed rule at idx was parsed from, or 0 if it was not parsed
+func (f IgnoreFile) lineAt(idx int) int {
+	return f.metaAt(idx).line
+}
+
+// Returns the line every rule was parsed from, in the order of allRules
+func (f IgnoreFile) allLines() []int {
+	metas := f.allMeta()
+
+	lines := make([]int, len(metas))
+	for idx, meta := range metas {
+		lines[idx] = meta.line
+	}
+
+	return lines
+}
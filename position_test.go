@@ -0,0 +1,92 @@
+package gignore
+
+import "testing"
+
+func TestPositionString(t *testing.T) {
+	tests := []struct {
+		name     string
+		position Position
+		expected string
+	}{
+		{name: "Pass-SourceAndLine", position: Position{Source: "services/api/.gitignore", Line: 42}, expected: "services/api/.gitignore:42"},
+		{name: "Pass-LineOnly", position: Position{Line: 3}, expected: "3"},
+		{name: "Pass-NotParsed", position: Position{Source: ".gitignore"}, expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if rendered := tc.position.String(); rendered != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, rendered)
+			}
+		})
+	}
+}
+
+func TestConflictPositions(t *testing.T) {
+	ignoreFile := NewIgnoreFile()
+	checkErrors("", Parse("# Build\nbuild/**\n\n*.log\nbuild/\n", &ignoreFile), t)
+	ignoreFile.SetSource(".gitignore")
+
+	conflicts := ignoreFile.FindConflicts()
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %v", conflicts)
+	}
+
+	if left := conflicts[0].LeftPosition; left != (Position{Source: ".gitignore", Line: 2}) {
+		t.Errorf("expected left position .gitignore:2, got %s", left)
+	}
+
+	if right := conflicts[0].RightPosition; right != (Position{Source: ".gitignore", Line: 5}) {
+		t.Errorf("expected right position .gitignore:5, got %s", right)
+	}
+}
+
+func TestDuplicateConflictPositions(t *testing.T) {
+	ignoreFile := NewIgnoreFile()
+	checkErrors("", Parse("*.log\nbuild/\n*.log\n", &ignoreFile), t)
+	ignoreFile.SetSource(".gitignore")
+
+	conflicts := ignoreFile.FindConflicts()
+	if len(conflicts) != 1 || conflicts[0].ConflictType != REDUNDANT_RULE {
+		t.Fatalf("expected 1 redundant rule conflict, got %v", conflicts)
+	}
+
+	if left := conflicts[0].LeftPosition; left != (Position{Source: ".gitignore", Line: 1}) {
+		t.Errorf("expected left position .gitignore:1, got %s", left)
+	}
+
+	if right := conflicts[0].RightPosition; right != (Position{Source: ".gitignore", Line: 3}) {
+		t.Errorf("expected right position .gitignore:3, got %s", right)
+	}
+
+	if position := ignoreFile.PositionOf(conflicts[0].Left); position.Line != 1 {
+		t.Errorf("expected PositionOf to return the first copy, got %s", position)
+	}
+}
+
+func TestResultPositions(t *testing.T) {
+	ignoreFile := NewIgnoreFile()
+	checkErrors("", Parse("*.log\n*.log\nlogs/**\nlogs/*.txt\n", &ignoreFile), t)
+	ignoreFile.SetSource(".gitignore")
+
+	fixes, err := ignoreFile.FixConflicts(5)
+	checkErrors("", err, t)
+
+	expected := map[string]Position{
+		"*.log":      {Source: ".gitignore", Line: 1}, // the first copy is removed
+		"logs/*.txt": {Source: ".gitignore", Line: 4},
+	}
+
+	for _, fix := range fixes {
+		if position := expected[fix.Rule.Render()]; fix.Position != position {
+			t.Errorf("expected %s to be reported at %s, got %s", fix.Log(), position, fix.Position)
+		}
+	}
+
+	added, err := ignoreFile.AddExtension("tmp", INCLUDE)
+	checkErrors("", err, t)
+
+	if added[0].Position.IsValid() {
+		t.Errorf("expected added rule to have no position, got %s", added[0].Position)
+	}
+}
@@ -0,0 +1,156 @@
+package gignore
+
+import "io"
+
+// DesiredState describes the rules an ignore file must contain.
+type DesiredState struct {
+	// Rules lists the rules that must be present, in their preferred order.
+	Rules []Ruler
+	// Prune removes every rule that is not listed in Rules.
+	Prune bool
+}
+
+// NewDesiredState creates a DesiredState from a list of rules.
+//
+// Parameters:
+//   - prune: Whether rules not listed in rules should be removed during reconciliation.
+//   - rules: The rules that must be present.
+//
+// Example:
+//
+//	logs, _ := NewExtensionRule("log", INCLUDE)
+//	build, _ := NewDirectoryRule("build", RECURSIVE, INCLUDE)
+//	desired := NewDesiredState(true, logs, build)
+func NewDesiredState(prune bool, rules ...Ruler) DesiredState {
+	return DesiredState{
+		Rules: rules,
+		Prune: prune,
+	}
+}
+
+// LoadDesiredState reads a DesiredState from any io.Reader containing ignore file syntax.
+// This allows policies to be kept as plain ignore files and enforced against other files.
+//
+// Parameters:
+//   - reader: Any io.Reader containing the desired rules in ignore file syntax.
+//   - prune: Whether rules not listed in the policy should be removed during reconciliation.
+//
+// Returns the DesiredState and an error if reading or parsing fails.
+//
+// Example:
+//
+//	policy, err := os.Open("policies/go.gitignore")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer policy.Close()
+//
+//	desired, err := LoadDesiredState(policy, false)
+func LoadDesiredState(reader io.Reader, prune bool) (DesiredState, error) {
+	var policy IgnoreFile
+	if err := LoadFile(reader, &policy); err != nil {
+		return DesiredState{}, err
+	}
+
+	return NewDesiredState(prune, policy.Rules()...), nil
+}
+
+func (d DesiredState) contains(rule Ruler) bool {
+	for _, desired := range d.Rules {
+		if rulesEqual(desired, rule) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Plan computes the changes Reconcile would make without modifying the IgnoreFile.
+//
+// Parameters:
+//   - desired: The state the IgnoreFile should be reconciled to.
+//
+// Returns the slice of Result that Reconcile would produce and an error if the desired
+// state cannot be reached (see Reconcile).
+//
+// Example:
+//
+//	plan, err := ignoreFile.Plan(desired)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, step := range plan {
+//	    fmt.Println(step.Log())
+//	}
+func (f IgnoreFile) Plan(desired DesiredState) ([]Result, error) {
+	working := f.clone()
+
+	return working.Reconcile(desired)
+}
+
+// Reconcile modifies the IgnoreFile so it matches a DesiredState. The reconciliation runs in
+// three steps:
+//  1. If Prune is set, every rule that is not part of the desired state is removed
+//  2. Every desired rule that is missing is added with automatic conflict resolution
+//  3. Remaining ordering problems are fixed with FixConflicts
+//
+// Parameters:
+//   - desired: The state the IgnoreFile should be reconciled to.
+//
+// Returns a slice of Result describing every change and an error. The error will be non-nil if:
+//   - A desired rule has a semantic conflict with, or is unreachable because of, another rule
+//   - Automatic conflict resolution fails
+//
+// The IgnoreFile is only modified if reconciliation succeeds. Running Reconcile against a
+// conflict-free file that already matches the desired state produces no results.
+//
+// Example:
+//
+//	results, err := ignoreFile.Reconcile(desired)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (f *IgnoreFile) Reconcile(desired DesiredState) ([]Result, error) {
+	working := f.clone()
+	results := make([]Result, 0)
+
+	if desired.Prune {
+		for _, rule := range f.rules {
+			if desired.contains(rule) {
+				continue
+			}
+
+			removal, err := working.deleteMatchingRule(rule, RECONCILED)
+			if err != nil {
+				return make([]Result, 0), err
+			}
+
+			results = append(results, removal)
+		}
+	}
+
+	for _, rule := range desired.Rules {
+		if working.containsRule(rule) {
+			continue
+		}
+
+		additions, err := working.addRuleWithConflictResolution(rule)
+		if err != nil {
+			return make([]Result, 0), err
+		}
+
+		additions[0].Reason = RECONCILED
+		results = append(results, additions...)
+	}
+
+	fixes, err := working.FixConflicts(20)
+	if err != nil {
+		return make([]Result, 0), err
+	}
+
+	results = append(results, fixes...)
+	*f = working
+
+	return results, nil
+}
@@ -0,0 +1,136 @@
+package gignore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name         string
+		ignore       IgnoreFile
+		desired      DesiredState
+		errorMessage string
+		resultCount  int
+		expected     []Ruler
+	}{
+		{
+			name: "Pass-AddMissing",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					FileRule{path: "todo.md", act: INCLUDE},
+				},
+			},
+			desired: NewDesiredState(false,
+				ExtensionRule{ext: "log", act: INCLUDE},
+			),
+			resultCount: 1,
+			expected: []Ruler{
+				FileRule{path: "todo.md", act: INCLUDE},
+				ExtensionRule{ext: "log", act: INCLUDE},
+			},
+		},
+		{
+			name: "Pass-Prune",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					FileRule{path: "todo.md", act: INCLUDE},
+					ExtensionRule{ext: "log", act: INCLUDE},
+				},
+			},
+			desired: NewDesiredState(true,
+				ExtensionRule{ext: "log", act: INCLUDE},
+			),
+			resultCount: 1,
+			expected: []Ruler{
+				ExtensionRule{ext: "log", act: INCLUDE},
+			},
+		},
+		{
+			name: "Pass-FixesOrdering",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					FileRule{path: "build/important.txt", act: EXCLUDE},
+				},
+			},
+			desired: NewDesiredState(false,
+				DirectoryRule{name: "build", mode: RECURSIVE, act: INCLUDE},
+			),
+			resultCount: 1,
+			expected: []Ruler{
+				DirectoryRule{name: "build", mode: RECURSIVE, act: INCLUDE},
+				FileRule{path: "build/important.txt", act: EXCLUDE},
+			},
+		},
+		{
+			name: "Pass-AlreadyReconciled",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					ExtensionRule{ext: "log", act: INCLUDE},
+				},
+			},
+			desired: NewDesiredState(true,
+				ExtensionRule{ext: "log", act: INCLUDE},
+			),
+			resultCount: 0,
+			expected: []Ruler{
+				ExtensionRule{ext: "log", act: INCLUDE},
+			},
+		},
+		{
+			name: "Fail-Unreachable",
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					ExtensionRule{ext: "log", act: INCLUDE},
+				},
+			},
+			desired: NewDesiredState(false,
+				FileRule{path: "debug.log", act: INCLUDE},
+			),
+			errorMessage: unreachableRuleError.Error(),
+			expected: []Ruler{
+				ExtensionRule{ext: "log", act: INCLUDE},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := tc.ignore.Plan(tc.desired)
+			checkErrors(tc.errorMessage, err, t)
+
+			results, err := tc.ignore.Reconcile(tc.desired)
+			checkErrors(tc.errorMessage, err, t)
+
+			if len(plan) != len(results) || len(results) != tc.resultCount {
+				t.Errorf("expected %d results, planned %d and applied %d", tc.resultCount, len(plan), len(results))
+			}
+
+			for _, result := range results {
+				if result.Reason != RECONCILED && result.Reason != AUTOMATED_FIX {
+					t.Errorf("unexpected reason %s", result.Reason)
+				}
+			}
+
+			if len(tc.ignore.rules) != len(tc.expected) {
+				t.Errorf("expected %d rules, found %d", len(tc.expected), len(tc.ignore.rules))
+				return
+			}
+
+			for idx, rule := range tc.ignore.rules {
+				if !rulesEqual(tc.expected[idx], rule) {
+					t.Errorf("Expected rule %s, found %s", tc.expected[idx].Render(), rule.Render())
+				}
+			}
+		})
+	}
+}
+
+func TestLoadDesiredState(t *testing.T) {
+	desired, err := LoadDesiredState(strings.NewReader("*.log\nbuild/**\n!build/important.txt\n"), true)
+	checkErrors("", err, t)
+
+	if len(desired.Rules) != 3 || !desired.Prune {
+		t.Errorf("expected 3 pruning rules, got %d (prune %t)", len(desired.Rules), desired.Prune)
+	}
+}
@@ -1,10 +1,120 @@
 package gignore
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
+
+// LineEnding is the line break style an ignore file is written with
+type LineEnding int
+
+const (
+	LF                   LineEnding = iota // "\n", the default
+	CRLF                                   // "\r\n", as checked out on Windows with core.autocrlf
+	CR                                     // "\r", as written by classic Mac OS
+	PRESERVE_LINE_ENDING                   // The style detected when the file was parsed, LF for new files
+)
+
+func (l LineEnding) String() string {
+	switch l {
+	case LF:
+		return "LF"
+	case CRLF:
+		return "CRLF"
+	case CR:
+		return "CR"
+	case PRESERVE_LINE_ENDING:
+		return "PRESERVE_LINE_ENDING"
+	default:
+		return ""
+	}
+}
+
+func (l LineEnding) separator() string {
+	switch l {
+	case CRLF:
+		return "\r\n"
+	case CR:
+		return "\r"
+	default:
+		return "\n"
+	}
+}
+
+// LineEnding returns the line break style detected when the IgnoreFile was parsed: CRLF or CR if
+// its first line ended in "\r\n" or "\r", LF otherwise.
+func (f IgnoreFile) LineEnding() LineEnding {
+	return f.lineEnding
+}
+
+// HasByteOrderMark reports whether the content the IgnoreFile was parsed from started with a
+// UTF-8 byte order mark.
+func (f IgnoreFile) HasByteOrderMark() bool {
+	return f.byteOrderMark
+}
+
+const byteOrderMark = "\uFEFF"
 
 type RenderOptions struct {
 	TrailingNewLine bool
-	HeaderComment   string
+	HeaderComment   string // May span several lines, each is prefixed with "# "
+	FooterComment   string // May span several lines, each is prefixed with "# "
+	LineEnding      LineEnding
+	ByteOrderMark   bool // Start the file with a UTF-8 byte order mark
+	// Start the file with a UTF-8 byte order mark if it had one when it was parsed
+	PreserveByteOrderMark bool
+}
+
+// Returns the lines of a comment, each prefixed with "# ", or "#" for empty lines. Returns nil
+// if there is no comment
+func commentLines(comment string) []string {
+	if comment == "" {
+		return nil
+	}
+
+	text := strings.ReplaceAll(comment, "\r\n", "\n")
+
+	lines := make([]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			lines = append(lines, "#")
+			continue
+		}
+
+		lines = append(lines, "# "+line)
+	}
+
+	return lines
+}
+
+// Returns lines without prefix, and the blank lines that followed it. Used so a header comment
+// that was rendered and parsed again is not written twice
+func withoutPrefix(lines, prefix []string) []string {
+	if len(prefix) == 0 || !slices.Equal(lines[:min(len(prefix), len(lines))], prefix) {
+		return lines
+	}
+
+	rest := lines[len(prefix):]
+	for len(rest) > 0 && rest[0] == "" {
+		rest = rest[1:]
+	}
+
+	return rest
+}
+
+// Returns lines without suffix, and the blank lines that preceded it
+func withoutSuffix(lines, suffix []string) []string {
+	if len(suffix) == 0 || len(suffix) > len(lines) || !slices.Equal(lines[len(lines)-len(suffix):], suffix) {
+		return lines
+	}
+
+	rest := lines[:len(lines)-len(suffix)]
+	for len(rest) > 0 && rest[len(rest)-1] == "" {
+		rest = rest[:len(rest)-1]
+	}
+
+	return rest
 }
 
 // Render converts an IgnoreFile to its string representation using the specified formatting options.
@@ -14,12 +124,28 @@ type RenderOptions struct {
 // Parameters:
 //   - ignoreFile: A pointer to the IgnoreFile instance to render.
 //   - options: The rendering options that control output formatting:
-//     TrailingNewLine adds a newline character at the end of the output if true.
-//     HeaderComment adds a comment line at the top of the file (prefixed with "# ")
-//     followed by a blank line if the comment is non-empty.
+//     TrailingNewLine adds a line break at the end of the output if true.
+//     HeaderComment adds a comment at the top of the file followed by a blank line, and
+//     FooterComment a blank line followed by a comment at the end, if they are non-empty.
+//     Every line of a multi-line comment is prefixed with "# ".
+//     LineEnding selects "\n" (LF, the default) or "\r\n" (CRLF) line breaks, or the style
+//     the IgnoreFile was parsed with (PRESERVE_LINE_ENDING).
+//     ByteOrderMark starts the output with a UTF-8 byte order mark if true, and
+//     PreserveByteOrderMark if the IgnoreFile was parsed from content that started with one.
 //
 // Returns a string containing the formatted ignore file content. Each rule appears on
 // its own line, with rules rendered in their current order within the IgnoreFile.
+// Whenever the section changes between two rules, a blank line and the new section's
+// header comment (prefixed with "# ") are written first. Comments that were directly above
+// a rule when it was parsed are written directly above it, and suppression directives that
+// were not are written at the top, after the header comment and the records of the templates
+// the file was generated from. Comments parsed from the top and bottom of the file are written
+// after HeaderComment and before FooterComment, unless they are the same comment, and other
+// comments that were separated from a rule by a blank line are written above it the same way.
+//
+// If the IgnoreFile has a managed block, the rules (and the header and footer comments) are rendered
+// between the MANAGED_BLOCK_BEGIN and MANAGED_BLOCK_END markers, and the content that
+// surrounded the block when it was parsed is written back unchanged.
 //
 // Example:
 //
@@ -38,18 +164,102 @@ type RenderOptions struct {
 func Render(ignoreFile *IgnoreFile, options RenderOptions) string {
 	var lines []string
 
+	if ignoreFile.block != nil {
+		lines = append(lines, ignoreFile.block.before...)
+		lines = append(lines, MANAGED_BLOCK_BEGIN)
+	}
+
+	start := len(lines)
+
 	if len(options.HeaderComment) > 0 {
-		lines = append(lines, "# "+options.HeaderComment)
+		lines = append(lines, commentLines(options.HeaderComment)...)
 		lines = append(lines, "") // blank line after header comment
 	}
 
-	for _, rule := range ignoreFile.Rules() {
+	rules := ignoreFile.Rules()
+	footer := withoutSuffix(ignoreFile.footer, commentLines(options.FooterComment))
+
+	if header := withoutPrefix(ignoreFile.header, commentLines(options.HeaderComment)); len(header) > 0 {
+		lines = append(lines, header...)
+		if len(ignoreFile.templates) > 0 || len(ignoreFile.directives) > 0 || len(rules) > 0 || len(footer) > 0 {
+			lines = append(lines, "") // blank line after the parsed header
+		}
+	}
+
+	if len(ignoreFile.templates) > 0 {
+		for _, record := range ignoreFile.templates {
+			lines = append(lines, record.comment())
+		}
+		if len(rules) > 0 || len(ignoreFile.directives) > 0 {
+			lines = append(lines, "") // blank line after template records
+		}
+	}
+
+	if len(ignoreFile.directives) > 0 {
+		lines = append(lines, ignoreFile.directives...)
+		if len(rules) > 0 {
+			lines = append(lines, "") // blank line after file level directives
+		}
+	}
+
+	previousSection := ""
+	for idx, rule := range rules {
+		meta := ignoreFile.metaAt(idx)
+		section := meta.section
+
+		if idx > 0 && section != previousSection {
+			lines = append(lines, "") // blank line between sections
+		}
+
+		if len(meta.detached) > 0 {
+			if len(lines) > start && lines[len(lines)-1] != "" {
+				lines = append(lines, "") // blank line before detached comments
+			}
+			lines = append(lines, meta.detached...)
+			lines = append(lines, "") // blank line after detached comments
+		}
+
+		if section != "" && (idx == 0 || section != previousSection) {
+			lines = append(lines, sectionHeader(section))
+		}
+
+		lines = append(lines, meta.comments...)
+
 		lines = append(lines, rule.Render())
+		previousSection = section
+	}
+
+	if len(footer) > 0 {
+		if len(lines) > start && lines[len(lines)-1] != "" {
+			lines = append(lines, "") // blank line before the parsed footer
+		}
+		lines = append(lines, footer...)
+	}
+
+	if len(options.FooterComment) > 0 {
+		if len(lines) > start {
+			lines = append(lines, "") // blank line before footer comment
+		}
+		lines = append(lines, commentLines(options.FooterComment)...)
+	}
+
+	if ignoreFile.block != nil {
+		lines = append(lines, MANAGED_BLOCK_END)
+		lines = append(lines, ignoreFile.block.after...)
 	}
 
-	result := strings.Join(lines, "\n")
+	ending := options.LineEnding
+	if ending == PRESERVE_LINE_ENDING {
+		ending = ignoreFile.lineEnding
+	}
+
+	result := strings.Join(lines, ending.separator())
 	if options.TrailingNewLine {
-		result += "\n"
+		result += ending.separator()
+	}
+
+	if options.ByteOrderMark || (options.PreserveByteOrderMark && ignoreFile.byteOrderMark) {
+		result = byteOrderMark + result
 	}
 
 	return result
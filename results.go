@@ -1,6 +1,14 @@
 package gignore
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	invalidActionResultError = errors.New("invalid action result")
+	invalidActionReasonError = errors.New("invalid action reason")
+)
 
 // ActionResult represents the outcome of an operation performed on a rule within an IgnoreFile
 type ActionResult int
@@ -16,6 +24,8 @@ const (
 	MOVED
 	// REMOVED indicates that an existing rule was deleted from the IgnoreFile.
 	REMOVED
+	// UNCHANGED indicates that the IgnoreFile already matched the requested state and nothing was modified.
+	UNCHANGED
 )
 
 func (a ActionResult) String() string {
@@ -30,11 +40,34 @@ func (a ActionResult) String() string {
 		return "ADDED"
 	case REMOVED:
 		return "REMOVED"
+	case UNCHANGED:
+		return "UNCHANGED"
 	default:
 		return ""
 	}
 }
 
+// MarshalText encodes the ActionResult as its name, e.g. "REMOVED".
+func (a ActionResult) MarshalText() ([]byte, error) {
+	if a.String() == "" {
+		return nil, invalidActionResultError
+	}
+
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an ActionResult encoded by MarshalText.
+func (a *ActionResult) UnmarshalText(text []byte) error {
+	for result := REVIEW_RECOMMENDED; result <= UNCHANGED; result++ {
+		if result.String() == string(text) {
+			*a = result
+			return nil
+		}
+	}
+
+	return invalidActionResultError
+}
+
 // ActionReason represents the cause or motivation behind an operation on a rule.
 type ActionReason int
 
@@ -46,6 +79,14 @@ const (
 	// FIX_UNKNOWN indicates the operation addresses a semantic conflict where the appropriate
 	// fix is not apparent enough to be performed automatically and requires manual intervention.
 	FIX_UNKNOWN
+	// RECONCILED indicates the operation was performed to bring the IgnoreFile in line with a DesiredState.
+	RECONCILED
+	// DEAD_RULE indicates the rule had no effect on the working tree it was checked against.
+	DEAD_RULE
+	// UPGRADED indicates the operation was performed to apply a change made upstream to a template.
+	UPGRADED
+	// MINIMIZED indicates the operation shrank the rule set without changing what it matches.
+	MINIMIZED
 )
 
 func (a ActionReason) String() string {
@@ -56,17 +97,48 @@ func (a ActionReason) String() string {
 		return "AUTOMATED_FIX"
 	case FIX_UNKNOWN:
 		return "FIX_UNKNOWN"
+	case RECONCILED:
+		return "RECONCILED"
+	case DEAD_RULE:
+		return "DEAD_RULE"
+	case UPGRADED:
+		return "UPGRADED"
+	case MINIMIZED:
+		return "MINIMIZED"
 	default:
 		return ""
 	}
 }
 
+// MarshalText encodes the ActionReason as its name, e.g. "AUTOMATED_FIX".
+func (a ActionReason) MarshalText() ([]byte, error) {
+	if a.String() == "" {
+		return nil, invalidActionReasonError
+	}
+
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an ActionReason encoded by MarshalText.
+func (a *ActionReason) UnmarshalText(text []byte) error {
+	for reason := REQUESTED; reason <= MINIMIZED; reason++ {
+		if reason.String() == string(text) {
+			*a = reason
+			return nil
+		}
+	}
+
+	return invalidActionReasonError
+}
+
 // Result represents the outcome of an operation performed on an IgnoreFile, including
 // details about what rule was affected, what happened to it, and why.
 type Result struct {
 	Rule   Ruler
 	Result ActionResult
 	Reason ActionReason
+	// Where Rule was parsed from, not valid for rules that were not parsed (such as added rules)
+	Position Position
 }
 
 // Log returns a formatted string representation of the Result suitable for logging
@@ -76,3 +148,13 @@ type Result struct {
 func (r Result) Log() string {
 	return fmt.Sprintf("%s: Rule '%s', Reason: %s", r.Result.String(), r.Rule.Render(), r.Reason.String())
 }
+
+// Changed reports whether the operation described by the Result modified the IgnoreFile.
+func (r Result) Changed() bool {
+	switch r.Result {
+	case ADDED, MOVED, REMOVED, FIXED:
+		return true
+	default:
+		return false
+	}
+}
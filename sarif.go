@@ -0,0 +1,316 @@
+package gignore
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// SARIF renders conflicts as a SARIF 2.1.0 log, the format code scanning services read to show
+// findings inline on pull requests. Only the parts of the format gignore needs are modelled.
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolURI = "https://github.com/MoonMoon1919/gignore"
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string          `json:"ruleId"`
+	RuleIndex        int             `json:"ruleIndex"`
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations,omitempty"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+	Fixes            []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               int                   `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion   `json:"deletedRegion"`
+	InsertedContent *sarifMessage `json:"insertedContent,omitempty"`
+}
+
+// MARK: Rules
+
+// Returns the SARIF level of a conflict type: contradicting rules are errors, rules that don't
+// do what they look like are warnings, and duplicates are notes
+func sarifLevel(conflictType ConflictType) string {
+	switch conflictType {
+	case SEMANTIC_CONFLICT:
+		return "error"
+	case REDUNDANT_RULE:
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+func sarifDescription(conflictType ConflictType) string {
+	switch conflictType {
+	case SEMANTIC_CONFLICT:
+		return "The same pattern is both ignored and re-included"
+	case REDUNDANT_RULE:
+		return "The same rule appears more than once"
+	case UNREACHABLE_RULE:
+		return "A broader rule with the same action already matches everything this rule matches"
+	case INEFFECTIVE_RULE:
+		return "A negation is overridden by a broader rule that comes after it"
+	default:
+		return string(conflictType)
+	}
+}
+
+func sarifRules() []sarifRule {
+	rules := make([]sarifRule, 0, len(conflictTypes))
+	for _, conflictType := range conflictTypes {
+		rules = append(rules, sarifRule{
+			ID:                   conflictTypeName(conflictType),
+			ShortDescription:     sarifMessage{Text: sarifDescription(conflictType)},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(conflictType)},
+		})
+	}
+
+	return rules
+}
+
+func sarifRuleIndex(conflictType ConflictType) int {
+	for idx, known := range conflictTypes {
+		if known == conflictType {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// MARK: Locations
+
+// Relative paths are resolved against the root of the scanned sources, absolute paths are file URIs
+func sarifArtifact(source string) sarifArtifactLocation {
+	uri := filepath.ToSlash(source)
+	if filepath.IsAbs(source) {
+		return sarifArtifactLocation{URI: "file://" + uri}
+	}
+
+	return sarifArtifactLocation{URI: strings.TrimPrefix(uri, "./"), URIBaseID: sarifSrcRoot}
+}
+
+func sarifLocationOf(position Position, message string) (sarifLocation, bool) {
+	if position.Source == "" {
+		return sarifLocation{}, false
+	}
+
+	location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifact(position.Source)}}
+	if position.IsValid() {
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: position.Line}
+	}
+	if message != "" {
+		location.Message = &sarifMessage{Text: message}
+	}
+
+	return location, true
+}
+
+// Returns the region covering a whole line, including its line break
+func sarifLineRegion(line int) sarifRegion {
+	return sarifRegion{StartLine: line, StartColumn: 1, EndLine: line + 1, EndColumn: 1}
+}
+
+// MARK: Fixes
+
+// Describes what FixConflicts would do about a conflict, by applying the fix to a copy of the
+// IgnoreFile. Returns false if the conflict is left for review or needs no change. The fix has no
+// artifact changes if the lines it edits are unknown
+func (f IgnoreFile) sarifFixFor(conflict Conflict) (sarifFix, bool) {
+	working := f.clone()
+
+	result, err := working.fixConflict(conflict)
+	if err != nil || result.Rule == nil {
+		return sarifFix{}, false
+	}
+
+	position := result.Position
+	fix := sarifFix{}
+	var replacements []sarifReplacement
+
+	switch result.Result {
+	case REMOVED:
+		fix.Description.Text = fmt.Sprintf("Remove '%s'", result.Rule.Render())
+
+		if position.IsValid() {
+			replacements = []sarifReplacement{{DeletedRegion: sarifLineRegion(position.Line)}}
+		}
+	case MOVED:
+		// Rules are only ever moved after the other rule of the conflict
+		target, targetPosition := conflict.Left, conflict.LeftPosition
+		if rulesEqual(result.Rule, conflict.Left) {
+			target, targetPosition = conflict.Right, conflict.RightPosition
+		}
+		fix.Description.Text = fmt.Sprintf("Move '%s' after '%s'", result.Rule.Render(), target.Render())
+
+		if position.IsValid() && targetPosition.IsValid() {
+			insertion := sarifRegion{StartLine: targetPosition.Line + 1, StartColumn: 1, EndLine: targetPosition.Line + 1, EndColumn: 1}
+			replacements = []sarifReplacement{
+				{DeletedRegion: sarifLineRegion(position.Line)},
+				{DeletedRegion: insertion, InsertedContent: &sarifMessage{Text: result.Rule.Render() + "\n"}},
+			}
+		}
+	default:
+		return sarifFix{}, false
+	}
+
+	if replacements != nil && position.Source != "" {
+		fix.ArtifactChanges = append(fix.ArtifactChanges, sarifArtifactChange{
+			ArtifactLocation: sarifArtifact(position.Source),
+			Replacements:     replacements,
+		})
+	}
+
+	return fix, true
+}
+
+// MARK: Serialization
+
+// ConflictsToSARIF serializes conflicts, such as those returned by IgnoreFile.FindConflicts or
+// Service.AnalyzeConflicts, as a SARIF 2.1.0 log for code scanning services.
+//
+// Every ConflictType is a SARIF rule whose ID is the ConflictType, spelled "REDUNDANT_RULE" for
+// REDUNDANT_RULE, and the message of each result is Conflict.Log. Each result is located at the later rule of the conflict
+// (Conflict.RightPosition), with the other rule as a related location. Locations need the
+// IgnoreFile's source to be known, see IgnoreFile.SetSource. Conflicts that FixConflicts can fix
+// carry a fix describing the removal or move it would make, with line edits that only cover the
+// rule's own line, not the comments above it. If the lines are unknown, the fix is described in
+// the result's message instead.
+//
+// Parameters:
+//   - ignoreFile: The IgnoreFile the conflicts were found in, used to work out their fixes.
+//   - conflicts: The conflicts to serialize.
+//
+// Returns the SARIF log as indented JSON, and an error if it cannot be encoded.
+//
+// Example:
+//
+//	conflicts := ignoreFile.FindConflicts()
+//	report, err := ConflictsToSARIF(ignoreFile, conflicts)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	os.WriteFile("gignore.sarif", report, 0o644)
+func ConflictsToSARIF(ignoreFile IgnoreFile, conflicts []Conflict) ([]byte, error) {
+	results := make([]sarifResult, 0, len(conflicts))
+
+	for _, conflict := range conflicts {
+		result := sarifResult{
+			RuleID:    conflictTypeName(conflict.ConflictType),
+			RuleIndex: sarifRuleIndex(conflict.ConflictType),
+			Level:     sarifLevel(conflict.ConflictType),
+			Message:   sarifMessage{Text: conflict.Log()},
+		}
+
+		primary, related := conflict.RightPosition, conflict.LeftPosition
+		other := conflict.Left
+		if !primary.IsValid() && related.IsValid() {
+			primary, related = related, primary
+			other = conflict.Right
+		}
+
+		if location, ok := sarifLocationOf(primary, ""); ok {
+			result.Locations = []sarifLocation{location}
+		}
+		if location, ok := sarifLocationOf(related, fmt.Sprintf("'%s'", other.Render())); ok && related != primary {
+			location.ID = 1
+			result.RelatedLocations = []sarifLocation{location}
+		}
+
+		// SARIF fixes must edit the file, fixes that can't be pointed at lines are only described
+		if fix, ok := ignoreFile.sarifFixFor(conflict); ok && len(fix.ArtifactChanges) > 0 {
+			result.Fixes = []sarifFix{fix}
+		} else if ok {
+			result.Message.Text += fmt.Sprintf(". Suggested fix: %s", fix.Description.Text)
+		}
+
+		results = append(results, result)
+	}
+
+	report := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "gignore", InformationURI: sarifToolURI, Rules: sarifRules()}},
+			Results: results,
+		}},
+	}
+
+	return json.MarshalIndent(report, "", "  ")
+}
@@ -0,0 +1,129 @@
+package gignore
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConflictsToSARIF(t *testing.T) {
+	ignoreFile := NewIgnoreFile()
+	checkErrors("", Parse("build/**\nbuild/\n!debug.log\n*.log\n", &ignoreFile), t)
+	ignoreFile.SetSource("services/api/.gitignore")
+
+	report, err := ConflictsToSARIF(ignoreFile, ignoreFile.FindConflicts())
+	checkErrors("", err, t)
+
+	var log sarifLog
+	if err := json.Unmarshal(report, &log); err != nil {
+		t.Fatalf("expected valid JSON, got %s", err.Error())
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("expected a SARIF 2.1.0 log with one run, got %+v", log)
+	}
+
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(conflictTypes) {
+		t.Errorf("expected a rule per conflict type, got %+v", run.Tool.Driver.Rules)
+	}
+
+	if len(run.Results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", run.Results)
+	}
+
+	tests := []struct {
+		name         string
+		result       sarifResult
+		ruleID       ConflictType
+		line         int
+		fix          string
+		replacements []sarifReplacement
+	}{
+		{
+			name:         "Pass-Unreachable",
+			result:       run.Results[0],
+			ruleID:       UNREACHABLE_RULE,
+			line:         2,
+			fix:          "Remove 'build/'",
+			replacements: []sarifReplacement{{DeletedRegion: sarifLineRegion(2)}},
+		},
+		{
+			name:   "Pass-Ineffective",
+			result: run.Results[1],
+			ruleID: INEFFECTIVE_RULE,
+			line:   4,
+			fix:    "Move '!debug.log' after '*.log'",
+			replacements: []sarifReplacement{
+				{DeletedRegion: sarifLineRegion(3)},
+				{DeletedRegion: sarifRegion{StartLine: 5, StartColumn: 1, EndLine: 5, EndColumn: 1}, InsertedContent: &sarifMessage{Text: "!debug.log\n"}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := tc.result
+			if result.RuleID != string(tc.ruleID) || run.Tool.Driver.Rules[result.RuleIndex].ID != result.RuleID {
+				t.Errorf("expected rule %s, got %s at index %d", tc.ruleID, result.RuleID, result.RuleIndex)
+			}
+
+			if len(result.Locations) != 1 {
+				t.Fatalf("expected 1 location, got %+v", result.Locations)
+			}
+
+			location := result.Locations[0].PhysicalLocation
+			if location.ArtifactLocation.URI != "services/api/.gitignore" || location.Region.StartLine != tc.line {
+				t.Errorf("expected services/api/.gitignore:%d, got %s:%d", tc.line, location.ArtifactLocation.URI, location.Region.StartLine)
+			}
+
+			if len(result.Fixes) != 1 || result.Fixes[0].Description.Text != tc.fix {
+				t.Fatalf("expected fix %q, got %+v", tc.fix, result.Fixes)
+			}
+
+			replacements := result.Fixes[0].ArtifactChanges[0].Replacements
+			if len(replacements) != len(tc.replacements) {
+				t.Fatalf("expected %d replacements, got %+v", len(tc.replacements), replacements)
+			}
+
+			for idx, replacement := range replacements {
+				expected := tc.replacements[idx]
+				if replacement.DeletedRegion != expected.DeletedRegion {
+					t.Errorf("expected region %+v, got %+v", expected.DeletedRegion, replacement.DeletedRegion)
+				}
+
+				if (replacement.InsertedContent == nil) != (expected.InsertedContent == nil) ||
+					(expected.InsertedContent != nil && replacement.InsertedContent.Text != expected.InsertedContent.Text) {
+					t.Errorf("expected inserted content %+v, got %+v", expected.InsertedContent, replacement.InsertedContent)
+				}
+			}
+		})
+	}
+}
+
+func TestConflictsToSARIFWithoutLines(t *testing.T) {
+	ignoreFile := NewIgnoreFile()
+	checkErrors("", Parse("*.log\n*.log\n", &ignoreFile), t)
+
+	report, err := ConflictsToSARIF(ignoreFile, ignoreFile.FindConflicts())
+	checkErrors("", err, t)
+
+	var log sarifLog
+	checkErrors("", json.Unmarshal(report, &log), t)
+
+	result := log.Runs[0].Results[0]
+	if result.RuleID != "REDUNDANT_RULE" || log.Runs[0].Tool.Driver.Rules[result.RuleIndex].ID != "REDUNDANT_RULE" {
+		t.Errorf("expected rule REDUNDANT_RULE, got %s at index %d", result.RuleID, result.RuleIndex)
+	}
+
+	if len(result.Locations) != 0 {
+		t.Errorf("expected no locations without a source, got %+v", result.Locations)
+	}
+
+	if len(result.Fixes) != 0 {
+		t.Errorf("expected a fix that can't be pointed at a line to be left out, got %+v", result.Fixes)
+	}
+
+	if expected := "REDUNDANT_RULE: Rules '*.log' and '*.log'. Suggested fix: Remove '*.log'"; result.Message.Text != expected {
+		t.Errorf("expected message %q, got %q", expected, result.Message.Text)
+	}
+}
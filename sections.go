@@ -0,0 +1,197 @@
+package gignore
+
+import "strings"
+
+// Sections group related rules under a header comment, e.g. "# Go" or "# IDE".
+//
+// A rule always belongs to the section of the position it occupies. Rules added without a
+// target section, and rules moved to fix a conflict, join the section of the rule they are
+// placed next to so that fixes never leave a rule stranded under an unrelated header.
+
+// SectionOf returns the name of the section a rule belongs to, or an empty string if the
+// rule is not part of a section. If the rule appears more than once, the section of the first
+// copy is returned.
+func (f IgnoreFile) SectionOf(rule Ruler) string {
+	idx := f.findRuleIndex(rule)
+	if idx == -1 {
+		return ""
+	}
+
+	return f.sectionAt(idx)
+}
+
+// Returns the section of the managed rule at idx
+func (f IgnoreFile) sectionAt(idx int) string {
+	return f.metaAt(idx).section
+}
+
+func (f *IgnoreFile) setSectionAt(idx int, section string) {
+	meta := f.metaAt(idx)
+	meta.section = section
+	f.setMetaAt(idx, meta)
+}
+
+// Sections returns the names of every section in the IgnoreFile, in the order they first appear.
+func (f IgnoreFile) Sections() []string {
+	sections := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for idx := range f.rules {
+		section := f.sectionAt(idx)
+		if section == "" || seen[section] {
+			continue
+		}
+
+		seen[section] = true
+		sections = append(sections, section)
+	}
+
+	return sections
+}
+
+// Assigns the rule at idx to the section of the rule it was inserted before, or after if it is last
+func (f *IgnoreFile) adoptNeighborSection(idx int) {
+	var neighbor int
+
+	switch {
+	case idx+1 < len(f.rules):
+		neighbor = idx + 1
+	case idx > 0:
+		neighbor = idx - 1
+	default:
+		return
+	}
+
+	f.setSectionAt(idx, f.sectionAt(neighbor))
+}
+
+// Returns the index just after the last rule of a section, or the end of the file if the section is empty
+func (f *IgnoreFile) sectionEnd(section string) int {
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		if f.sectionAt(i) == section {
+			return i + 1
+		}
+	}
+
+	return len(f.rules)
+}
+
+func sectionHeader(section string) string {
+	return "# " + section
+}
+
+// Section headers start a block of comments that directly precedes a rule
+func isSectionHeader(lines []string, idx int, previousBlank bool) bool {
+	line := strings.TrimSpace(lines[idx])
+	if !previousBlank || !strings.HasPrefix(line, "#") {
+		return false
+	}
+
+	if isManagedBlockBegin(line) || isManagedBlockEnd(line) || isDirective(line) || sectionName(line) == "" {
+		return false
+	}
+
+	for _, next := range lines[idx+1:] {
+		next = strings.TrimSpace(next)
+
+		switch {
+		case next == "":
+			return false
+		case strings.HasPrefix(next, "#"):
+			continue
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
+func sectionName(line string) string {
+	return strings.TrimSpace(strings.TrimPrefix(line, "#"))
+}
+
+// AddToSection adds a rule to the end of a named section with automatic conflict detection
+// and resolution. If the section does not exist yet, it is created at the end of the file.
+//
+// Parameters:
+//   - section: The name of the section to add the rule to (e.g., "Go", "IDE").
+//   - rule: The rule to add, created with one of the rule constructors.
+//
+// Returns a slice of Result containing the addition operation and any subsequent conflict
+// fixes, plus an error. The error will be non-nil if:
+//   - A semantic conflict, redundant rule, or unreachable rule is detected
+//   - Automatic conflict resolution fails
+//
+// If the rule has to be placed before a rule in another section to take effect (for example
+// a broad rule that must precede an existing exception), it joins that section instead.
+//
+// Example:
+//
+//	rule, _ := NewDirectoryRule(".idea", DIRECTORY, INCLUDE)
+//	results, err := ignoreFile.AddToSection("IDE", rule)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (f *IgnoreFile) AddToSection(section string, rule Ruler) ([]Result, error) {
+	defaultInsertionPoint := f.sectionEnd(section)
+
+	idx, err := f.insertRule(rule, defaultInsertionPoint)
+	if err != nil {
+		return make([]Result, 0), err
+	}
+
+	if idx == defaultInsertionPoint {
+		f.setSectionAt(idx, section)
+	} else {
+		f.adoptNeighborSection(idx)
+	}
+
+	return f.resolveAddition(rule)
+}
+
+// MoveRuleToSection relocates an existing rule to the end of a named section. If the section
+// does not exist yet, the rule is moved to the end of the file and starts the section.
+//
+// Parameters:
+//   - rule: The rule to move. Must exactly match an existing rule in the IgnoreFile.
+//   - section: The name of the destination section.
+//   - reason: The reason for the move operation.
+//
+// Returns a Result containing details of the move operation and an error. The error will be
+// non-nil if the rule is not found. No move occurs if the rule already belongs to the section.
+//
+// Like MoveRule, the method does not check whether the new position changes which rule
+// takes effect; run FindConflicts afterwards if the move may affect precedence.
+//
+// Example:
+//
+//	rule, _ := NewExtensionRule("log", INCLUDE)
+//	result, err := ignoreFile.MoveRuleToSection(rule, "Logs", REQUESTED)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (f *IgnoreFile) MoveRuleToSection(rule Ruler, section string, reason ActionReason) (Result, error) {
+	moveIdx := f.findRuleIndex(rule)
+	if moveIdx == -1 {
+		return Result{}, ruleToMoveNotFoundError
+	}
+
+	if f.sectionAt(moveIdx) == section {
+		return Result{}, nil
+	}
+
+	movedIdx, err := f.moveRule(moveIdx, f.sectionEnd(section))
+	if err != nil {
+		return Result{}, err
+	}
+
+	f.setSectionAt(movedIdx, section)
+
+	return Result{
+		Rule:     rule,
+		Result:   MOVED,
+		Reason:   reason,
+		Position: f.managedPosition(movedIdx),
+	}, nil
+}
@@ -0,0 +1,189 @@
+package gignore
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSections(t *testing.T) {
+	content := `# Generated ignore file
+
+*.tmp
+
+# Go
+*.test
+# Test binary, built with go test -c
+*.out
+
+# IDE
+.idea/
+.vscode/`
+
+	ignore := NewIgnoreFile()
+	Parse(content, &ignore)
+
+	if sections := ignore.Sections(); !reflect.DeepEqual(sections, []string{"Go", "IDE"}) {
+		t.Errorf("expected sections [Go IDE], got %v", sections)
+	}
+
+	tests := []struct {
+		rule    Ruler
+		section string
+	}{
+		{rule: ExtensionRule{ext: "tmp", act: INCLUDE}, section: ""},
+		{rule: ExtensionRule{ext: "test", act: INCLUDE}, section: "Go"},
+		{rule: ExtensionRule{ext: "out", act: INCLUDE}, section: "Go"},
+		{rule: DirectoryRule{name: ".idea", mode: DIRECTORY, act: INCLUDE}, section: "IDE"},
+		{rule: DirectoryRule{name: ".vscode", mode: DIRECTORY, act: INCLUDE}, section: "IDE"},
+	}
+
+	for _, tc := range tests {
+		if section := ignore.SectionOf(tc.rule); section != tc.section {
+			t.Errorf("expected %s to be in section %q, found %q", tc.rule.Render(), tc.section, section)
+		}
+	}
+}
+
+func TestRenderSections(t *testing.T) {
+	content := `*.tmp
+
+# Go
+*.test
+
+# IDE
+.idea/`
+
+	ignore := NewIgnoreFile()
+	Parse(content, &ignore)
+
+	if rendered := Render(&ignore, RenderOptions{}); rendered != content {
+		t.Errorf("expected:\n%s\ngot:\n%s", content, rendered)
+	}
+}
+
+func TestAddToSection(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		section      string
+		rule         Ruler
+		errorMessage string
+		expected     string
+	}{
+		{
+			name:    "Pass-ExistingSection",
+			content: "# Go\n*.test\n\n# IDE\n.idea/",
+			section: "Go",
+			rule:    ExtensionRule{ext: "out", act: INCLUDE},
+			expected: `# Go
+*.test
+*.out
+
+# IDE
+.idea/`,
+		},
+		{
+			name:    "Pass-NewSection",
+			content: "# Go\n*.test",
+			section: "Secrets",
+			rule:    ExtensionRule{ext: "pem", act: INCLUDE},
+			expected: `# Go
+*.test
+
+# Secrets
+*.pem`,
+		},
+		{
+			name:    "Pass-JoinsSectionOfException",
+			content: "# Build\n!build/important.txt\n\n# Logs\n*.log",
+			section: "Logs",
+			rule:    DirectoryRule{name: "build", mode: RECURSIVE, act: INCLUDE},
+			expected: `# Build
+build/**
+!build/important.txt
+
+# Logs
+*.log`,
+		},
+		{
+			name:         "Fail-Redundant",
+			content:      "# Go\n*.test",
+			section:      "IDE",
+			rule:         ExtensionRule{ext: "test", act: INCLUDE},
+			errorMessage: redundantRuleError.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore := NewIgnoreFile()
+			Parse(tc.content, &ignore)
+
+			_, err := ignore.AddToSection(tc.section, tc.rule)
+			checkErrors(tc.errorMessage, err, t)
+			if tc.errorMessage != "" {
+				return
+			}
+
+			if rendered := Render(&ignore, RenderOptions{}); rendered != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, rendered)
+			}
+		})
+	}
+}
+
+func TestMoveRuleToSection(t *testing.T) {
+	content := "# Go\n*.test\n*.idea\n\n# IDE\n.vscode/"
+
+	ignore := NewIgnoreFile()
+	Parse(content, &ignore)
+
+	result, err := ignore.MoveRuleToSection(ExtensionRule{ext: "idea", act: INCLUDE}, "IDE", REQUESTED)
+	checkErrors("", err, t)
+
+	if result.Result != MOVED {
+		t.Errorf("expected rule to be moved, got %s", result.Result)
+	}
+
+	expected := "# Go\n*.test\n\n# IDE\n.vscode/\n*.idea"
+	if rendered := Render(&ignore, RenderOptions{}); rendered != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, rendered)
+	}
+
+	_, err = ignore.MoveRuleToSection(FileRule{path: "todo.md", act: INCLUDE}, "IDE", REQUESTED)
+	checkErrors(ruleToMoveNotFoundError.Error(), err, t)
+}
+
+func TestFixConflictKeepsSections(t *testing.T) {
+	content := `# Exceptions
+!build/important.txt
+
+# Build
+build/**`
+
+	ignore := NewIgnoreFile()
+	Parse(content, &ignore)
+
+	_, err := ignore.FixConflicts(5)
+	checkErrors("", err, t)
+
+	expected := "# Build\nbuild/**\n!build/important.txt"
+	if rendered := Render(&ignore, RenderOptions{}); rendered != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, rendered)
+	}
+}
+
+func TestRenderRepeatedRuleInSections(t *testing.T) {
+	content := "# A\n*.log\n\n# B\n# Written by the test runner\n*.log\n"
+
+	ignore := NewIgnoreFile()
+	checkErrors("", Parse(content, &ignore), t)
+
+	if rendered := Render(&ignore, RenderOptions{TrailingNewLine: true}); rendered != content {
+		t.Errorf("expected:\n%s\ngot:\n%s", content, rendered)
+	}
+
+	if sections := ignore.Sections(); !reflect.DeepEqual(sections, []string{"A", "B"}) {
+		t.Errorf("expected sections A and B, got %v", sections)
+	}
+}
@@ -1,5 +1,7 @@
 package gignore
 
+import "io/fs"
+
 type Repository interface {
 	Load(path string, ignoreFile *IgnoreFile) error
 	Save(path string, ignoreFile *IgnoreFile) error
@@ -13,13 +15,178 @@ func NewService(repo Repository) Service {
 	return Service{repo: repo}
 }
 
-// Creates a new ignore file
-func (s *Service) Init(path string) error {
-	ignore := NewIgnoreFile()
+// Init creates a new ignore file. Without templates the file is empty, otherwise it contains the
+// named embedded templates composed with ComposeTemplates, one labelled section per template.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to create.
+//   - templates: The names of the templates to compose, e.g. "go", "jetbrains". See TemplateNames.
+//
+// Returns an error if a template is unknown or the file cannot be saved.
+//
+// Example:
+//
+//	if err := service.Init(".gitignore", "go", "vscode", "macos"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) Init(path string, templates ...string) error {
+	return s.InitFromSource(path, EmbeddedTemplates(), templates...)
+}
+
+// InitFromSource creates a new ignore file from templates of a TemplateSource, such as a local
+// directory of curated templates. See Init.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to create.
+//   - source: The TemplateSource to load the templates from.
+//   - templates: The names of the templates to compose.
+//
+// Returns an error if a template cannot be loaded or the file cannot be saved.
+//
+// Example:
+//
+//	source := NewDirTemplateSource("/opt/company-templates")
+//	if err := service.InitFromSource(".gitignore", source, "base", "services"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) InitFromSource(path string, source TemplateSource, templates ...string) error {
+	ignore, _, err := ComposeTemplatesFrom(source, templates...)
+	if err != nil {
+		return err
+	}
 
 	return s.repo.Save(path, &ignore)
 }
 
+// AddTemplate adds the rules of a template to an existing ignore file using an atomic
+// load-modify-save operation. See IgnoreFile.AddTemplate.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to modify.
+//   - source: The TemplateSource to load the template from, e.g. EmbeddedTemplates().
+//   - name: The name of the template.
+//
+// Returns a slice of Result describing every change and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - The template cannot be loaded
+//   - Automatic conflict resolution fails
+//   - The updated ignore file cannot be saved
+//
+// Example:
+//
+//	results, err := service.AddTemplate(".gitignore", EmbeddedTemplates(), "vscode")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) AddTemplate(path string, source TemplateSource, name string) ([]Result, error) {
+	var results []Result
+
+	err := s.loadModifySave(path, func(f *IgnoreFile) error {
+		var err error
+		results, err = f.AddTemplate(source, name)
+		return err
+	})
+
+	return results, err
+}
+
+// FindTemplateDrift loads an ignore file and compares it with the current version of each
+// template it was generated from, without modifying the file. See IgnoreFile.FindTemplateDrift.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to check.
+//   - source: The TemplateSource holding the current templates, e.g. EmbeddedTemplates().
+//
+// Returns a slice of Drift and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - A recorded template cannot be loaded
+//
+// Example:
+//
+//	drift, err := service.FindTemplateDrift(".gitignore", EmbeddedTemplates())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) FindTemplateDrift(path string, source TemplateSource) ([]Drift, error) {
+	var ignoreFile IgnoreFile
+	if err := s.load(path, &ignoreFile); err != nil {
+		return nil, err
+	}
+
+	return ignoreFile.FindTemplateDrift(source)
+}
+
+// Upgrade merges the current version of each template an ignore file was generated from with
+// its local edits using an atomic load-modify-save operation. See IgnoreFile.Upgrade.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to modify.
+//   - source: The TemplateSource holding the current templates, e.g. EmbeddedTemplates().
+//
+// Returns a slice of Result describing every change and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - A recorded template cannot be loaded
+//   - Automatic conflict resolution fails
+//   - The updated ignore file cannot be saved
+//
+// Example:
+//
+//	results, err := service.Upgrade(".gitignore", EmbeddedTemplates())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) Upgrade(path string, source TemplateSource) ([]Result, error) {
+	var results []Result
+
+	err := s.loadModifySave(path, func(f *IgnoreFile) error {
+		var err error
+		results, err = f.Upgrade(source)
+		return err
+	})
+
+	return results, err
+}
+
+// Merge performs a rule-level three-way merge of an ignore file and saves the result over ours,
+// which is what git expects from a merge driver. See Merge.
+//
+// Parameters:
+//   - basePath: The file system path to the common ancestor.
+//   - oursPath: The file system path to the current version, overwritten with the merged file.
+//   - theirsPath: The file system path to the version being merged.
+//
+// Returns the conflicts the merge introduced and the parts of the file that could not be merged,
+// see Merge, and an error. The merged file is saved either way, with conflicting lines between
+// conflict markers. The error will be non-nil if:
+//   - Any of the three ignore files cannot be loaded
+//   - The merged ignore file cannot be saved
+//
+// Example:
+//
+//	conflicts, mergeConflicts, err := service.Merge(".merge_file_base", ".gitignore", ".merge_file_theirs")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) Merge(basePath, oursPath, theirsPath string) ([]Conflict, []MergeConflict, error) {
+	var base, ours, theirs IgnoreFile
+	if err := s.load(basePath, &base); err != nil {
+		return nil, nil, err
+	}
+	if err := s.load(oursPath, &ours); err != nil {
+		return nil, nil, err
+	}
+	if err := s.load(theirsPath, &theirs); err != nil {
+		return nil, nil, err
+	}
+
+	merged, conflicts, mergeConflicts := Merge(base, ours, theirs)
+	if err := s.repo.Save(oursPath, &merged); err != nil {
+		return nil, nil, err
+	}
+
+	return conflicts, mergeConflicts, nil
+}
+
 // MARK: Add methods
 
 // AddFileRule adds a new file rule to an ignore file using an atomic load-modify-save operation.
@@ -364,6 +531,118 @@ func (s *Service) DeleteGlobRule(path, pattern string, action Action) (Result, e
 	return results, err
 }
 
+// MARK: Section methods
+
+// AddToSection adds a rule to a named section of an ignore file using an atomic load-modify-save
+// operation. The rule is added as given, so a FileRule for "foo*" stays a file rule rather than
+// being parsed as a glob. The section is created at the end of the file if it does not exist yet.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to modify.
+//   - section: The name of the section to add the rule to (e.g., "Go", "IDE", "Secrets").
+//   - rule: The rule to add, created with NewFileRule, NewExtensionRule, NewDirectoryRule or NewGlobRule.
+//
+// Returns a slice of Result containing the addition operation and any subsequent conflict
+// fixes, plus an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - A semantic conflict, redundant rule, or unreachable rule is detected
+//   - Automatic conflict resolution fails
+//   - The updated ignore file cannot be saved
+//
+// Example:
+//
+//	rule, _ := NewFileRule("draft*.md", INCLUDE)
+//	results, err := service.AddToSection(".gitignore", "Drafts", rule)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) AddToSection(path, section string, rule Ruler) ([]Result, error) {
+	var results []Result
+
+	err := s.loadModifySave(path, func(f *IgnoreFile) error {
+		var err error
+		results, err = f.AddToSection(section, rule)
+		return err
+	})
+
+	return results, err
+}
+
+// AddRuleToSection adds a rule to a named section of an ignore file using an atomic load-modify-save operation.
+// The rule pattern is parsed the same way as MoveRule, use AddToSection to add a rule of a specific
+// kind. The section is created at the end of the file if it does not exist yet.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to modify.
+//   - section: The name of the section to add the rule to (e.g., "Go", "IDE", "Secrets").
+//   - rulePattern: The pattern string representing the rule (e.g., ".idea/", "*.pem").
+//
+// Returns a slice of Result containing the addition operation and any subsequent conflict
+// fixes, plus an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - The rule pattern cannot be parsed or is invalid
+//   - A semantic conflict, redundant rule, or unreachable rule is detected
+//   - Automatic conflict resolution fails
+//   - The updated ignore file cannot be saved
+//
+// Example:
+//
+//	results, err := service.AddRuleToSection(".gitignore", "Secrets", "*.pem")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) AddRuleToSection(path, section, rulePattern string) ([]Result, error) {
+	var results []Result
+
+	err := s.loadModifySave(path, func(f *IgnoreFile) error {
+		rule, err := parseRule(rulePattern)
+		if err != nil {
+			return err
+		}
+
+		results, err = f.AddToSection(section, rule)
+		return err
+	})
+
+	return results, err
+}
+
+// MoveRuleToSection relocates a rule to the end of a named section of an ignore file using an atomic
+// load-modify-save operation. The rule pattern is parsed the same way as MoveRule.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to modify.
+//   - rulePattern: The pattern string representing the rule to move.
+//   - section: The name of the destination section.
+//
+// Returns a Result containing details of the move operation and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - The rule pattern cannot be parsed or is invalid
+//   - The rule to move is not found in the ignore file
+//   - The updated ignore file cannot be saved
+//
+// Example:
+//
+//	result, err := service.MoveRuleToSection(".gitignore", ".vscode/", "IDE")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) MoveRuleToSection(path, rulePattern, section string) (Result, error) {
+	var result Result
+
+	err := s.loadModifySave(path, func(f *IgnoreFile) error {
+		rule, err := parseRule(rulePattern)
+		if err != nil {
+			return err
+		}
+
+		result, err = f.MoveRuleToSection(rule, section, REQUESTED)
+		return err
+	})
+
+	return result, err
+}
+
 // MARK: Move methods
 
 // MoveRule relocates an existing rule relative to another rule in an ignore file using an atomic load-modify-save operation.
@@ -420,103 +699,634 @@ func (s *Service) MoveRule(path, rulePattern, targetRulePattern string, directio
 	return results, err
 }
 
-// MARK: Fixers
+// MARK: Ensure methods
 
-// AutoFix automatically resolves conflicts in an ignore file using an atomic load-modify-save operation.
-// The method loads the ignore file from the specified path, runs multiple passes of conflict detection
-// and resolution, then saves the updated file.
+// EnsurePresent makes sure a rule exists in an ignore file using an atomic load-modify-save operation.
+// The rule pattern is parsed the same way as MoveRule. If the rule already exists the call
+// succeeds without modifying the rules, which makes it safe to run repeatedly.
 //
 // Parameters:
-//   - path: The file system path to the ignore file to analyze and fix.
-//   - maxPasses: The maximum number of conflict resolution passes to attempt. Each pass finds all
-//     current conflicts and attempts to fix them before checking again. The method will stop early
-//     if no conflicts are found in a given pass.
+//   - path: The file system path to the ignore file to modify.
+//   - rulePattern: The pattern string representing the rule (e.g., "*.log", "!build/important.txt").
 //
-// Returns a slice of Result containing descriptions of all fixes applied and an error.
+// Returns a slice of Result and an error. The slice contains a single UNCHANGED result if the rule
+// was already present, otherwise the addition and any subsequent conflict fixes.
 // The error will be non-nil if:
 //   - The ignore file cannot be loaded from the specified path
-//   - Any conflict resolution attempt fails
+//   - The rule pattern cannot be parsed or is invalid
+//   - A semantic conflict or unreachable rule is detected
 //   - The updated ignore file cannot be saved
 //
 // Example:
 //
-//	fixes, err := service.AutoFix(".gitignore", 5)
+//	results, err := service.EnsurePresent(".gitignore", "node_modules/")
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 //
-//	if len(fixes) > 0 {
-//	    fmt.Printf("Applied %d fixes:\n", len(fixes))
-//	    for _, fix := range fixes {
-//	        fmt.Printf("- %s\n", fix.Log())
-//	    }
-//	} else {
-//	    fmt.Println("No conflicts found")
+//	for _, result := range results {
+//	    fmt.Println(result.Log())
 //	}
-func (s *Service) AutoFix(path string, maxPasses int) ([]Result, error) {
-	var fixes []Result
+func (s *Service) EnsurePresent(path, rulePattern string) ([]Result, error) {
+	var results []Result
 
 	err := s.loadModifySave(path, func(f *IgnoreFile) error {
-		var err error
-		fixes, err = f.FixConflicts(maxPasses)
+		rule, err := parseRule(rulePattern)
+		if err != nil {
+			return err
+		}
+
+		results, err = f.EnsurePresent(rule)
 		return err
 	})
 
-	return fixes, err
+	return results, err
 }
 
-// MARK: Analyzers
-
-// AnalyzeConflicts loads an ignore file and returns all detected conflicts without making any modifications.
-// This method provides a read-only analysis of rule conflicts, useful for inspection and reporting
-// before deciding whether to apply automatic fixes.
+// EnsureAbsent makes sure a rule does not exist in an ignore file using an atomic load-modify-save operation.
+// The rule pattern is parsed the same way as MoveRule. If the rule is already missing the call
+// succeeds without modifying the rules.
 //
 // Parameters:
-//   - path: The file system path to the ignore file to analyze.
+//   - path: The file system path to the ignore file to modify.
+//   - rulePattern: The pattern string representing the rule (e.g., "*.log", "!build/important.txt").
 //
-// Returns a slice of Conflict containing all detected conflicts and an error.
-// The error will be non-nil if:
+// Returns a Result that is REMOVED if the rule was deleted or UNCHANGED if it was not present,
+// and an error. Every copy of the rule is removed. The error will be non-nil if:
 //   - The ignore file cannot be loaded from the specified path
-//
-// Unlike AutoFix, this method performs no modifications to the file - it only reads and analyzes.
-// The conflicts are detected using comprehensive pairwise comparison of all rules, considering
-// rule order and any intervening rules between each pair.
-//
-// Conflict types that may be detected:
-//   - SEMANTIC_CONFLICT: Rules that contradict each other in meaning
-//   - REDUNDANT_RULE: Rules that duplicate existing functionality
-//   - UNREACHABLE_RULE: Rules that can never be triggered due to earlier rules
-//   - INEFFECTIVE_RULE: Rules that would be more effective in a different position
+//   - The rule pattern cannot be parsed or is invalid
+//   - A copy of the rule is outside of the managed block
+//   - The updated ignore file cannot be saved
 //
 // Example:
 //
-//	conflicts, err := service.AnalyzeConflicts(".gitignore")
+//	result, err := service.EnsureAbsent(".gitignore", "todo.md")
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 //
-//	if len(conflicts) > 0 {
-//	    fmt.Printf("Found %d conflicts:\n", len(conflicts))
-//	    for _, conflict := range conflicts {
-//	        fmt.Printf("- %s\n", conflict)
-//	    }
-//	} else {
-//	    fmt.Println("No conflicts detected")
+//	if result.Changed() {
+//	    fmt.Println(result.Log())
 //	}
-func (s *Service) AnalyzeConflicts(path string) ([]Conflict, error) {
-	var ignoreFile IgnoreFile
-	if err := s.repo.Load(path, &ignoreFile); err != nil {
-		return nil, err
-	}
+func (s *Service) EnsureAbsent(path, rulePattern string) (Result, error) {
+	var result Result
 
-	return ignoreFile.FindConflicts(), nil
+	err := s.loadModifySave(path, func(f *IgnoreFile) error {
+		rule, err := parseRule(rulePattern)
+		if err != nil {
+			return err
+		}
+
+		result, err = f.EnsureAbsent(rule)
+		return err
+	})
+
+	return result, err
+}
+
+// MARK: Batch methods
+
+// Batch applies a sequence of operations to an ignore file using a single load-modify-save operation.
+// The method loads the ignore file once, applies every operation in order, and only saves the
+// updated file if all of them succeed. A failure partway through leaves the file on disk untouched.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to modify.
+//   - operations: The operations to apply, in order (e.g., AddFileOperation, DeleteGlobOperation,
+//     MoveRuleOperation).
+//
+// Returns the combined slice of Result from every operation and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - Any operation fails validation or detects a conflict it cannot resolve
+//   - The updated ignore file cannot be saved
+//
+// Example:
+//
+//	results, err := service.Batch(".gitignore",
+//	    AddExtensionOperation("log", INCLUDE),
+//	    AddDirectoryOperation("node_modules", RECURSIVE, INCLUDE),
+//	    DeleteFileOperation("todo.md", INCLUDE),
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, result := range results {
+//	    fmt.Println(result.Log())
+//	}
+func (s *Service) Batch(path string, operations ...Operation) ([]Result, error) {
+	var results []Result
+
+	err := s.loadModifySave(path, func(f *IgnoreFile) error {
+		var err error
+		results, err = f.Batch(operations...)
+		return err
+	})
+
+	return results, err
+}
+
+// MARK: Reconciliation
+
+// PlanReconcile loads an ignore file and returns the changes Reconcile would make, without
+// modifying the file. Use it to review a reconciliation before applying it.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to compare against the desired state.
+//   - desired: The state the ignore file should be reconciled to.
+//
+// Returns a slice of Result describing the planned changes and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - The desired state cannot be reached (see IgnoreFile.Reconcile)
+//
+// Example:
+//
+//	plan, err := service.PlanReconcile(".gitignore", desired)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, step := range plan {
+//	    fmt.Println(step.Log())
+//	}
+func (s *Service) PlanReconcile(path string, desired DesiredState) ([]Result, error) {
+	var ignoreFile IgnoreFile
+	if err := s.load(path, &ignoreFile); err != nil {
+		return nil, err
+	}
+
+	return ignoreFile.Plan(desired)
+}
+
+// Reconcile brings an ignore file in line with a desired state using an atomic load-modify-save operation.
+// Missing rules are added, unlisted rules are removed when the desired state prunes, and ordering
+// problems are fixed using the existing conflict resolution.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to modify.
+//   - desired: The state the ignore file should be reconciled to.
+//
+// Returns a slice of Result describing every change and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - The desired state cannot be reached (see IgnoreFile.Reconcile)
+//   - The updated ignore file cannot be saved
+//
+// Example:
+//
+//	desired, err := LoadDesiredState(policy, true)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	results, err := service.Reconcile(".gitignore", desired)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) Reconcile(path string, desired DesiredState) ([]Result, error) {
+	var results []Result
+
+	err := s.loadModifySave(path, func(f *IgnoreFile) error {
+		var err error
+		results, err = f.Reconcile(desired)
+		return err
+	})
+
+	return results, err
+}
+
+// MARK: Policies
+
+// ExportPolicy loads an ignore file and describes it as a Policy, a structured document that can
+// be encoded as JSON, YAML or TOML. See NewPolicy. This method performs no modifications.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to export.
+//
+// Returns the Policy and an error if the ignore file cannot be loaded.
+//
+// Example:
+//
+//	policy, err := service.ExportPolicy(".gitignore")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	encoded, err := json.MarshalIndent(policy, "", "  ")
+func (s *Service) ExportPolicy(path string) (Policy, error) {
+	var ignoreFile IgnoreFile
+	if err := s.load(path, &ignoreFile); err != nil {
+		return Policy{}, err
+	}
+
+	return NewPolicy(ignoreFile), nil
+}
+
+// ImportPolicy renders a Policy into an ignore file, creating or replacing it. See Policy.IgnoreFile.
+//
+// Parameters:
+//   - path: The file system path where the ignore file should be saved.
+//   - policy: The Policy describing the rules of the file.
+//
+// Returns an error if an entry of the Policy is invalid, in which case nothing is written, or if
+// the ignore file cannot be saved. Invalid entries are reported as PolicyErrors with their path
+// in the document.
+//
+// Example:
+//
+//	err := service.ImportPolicy(".gitignore", policy)
+//	if err != nil {
+//	    log.Fatal(err) // rules[2].mode: invalid directory mode
+//	}
+func (s *Service) ImportPolicy(path string, policy Policy) error {
+	ignoreFile, err := policy.IgnoreFile()
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Save(path, &ignoreFile)
+}
+
+// MARK: Managed blocks
+
+// EnableManagedBlock wraps the rules of an ignore file in a managed block using an atomic
+// load-modify-save operation. Afterwards, content added by hand outside of the
+// MANAGED_BLOCK_BEGIN and MANAGED_BLOCK_END markers is left untouched by every Service operation.
+// Files that already contain a managed block are saved unchanged.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to modify.
+//
+// Returns an error if the ignore file cannot be loaded or saved.
+//
+// Example:
+//
+//	if err := service.EnableManagedBlock(".gitignore"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) EnableManagedBlock(path string) error {
+	return s.loadModifySave(path, func(f *IgnoreFile) error {
+		f.EnableManagedBlock()
+		return nil
+	})
+}
+
+// MARK: Dead rules
+
+// FindDeadRules loads an ignore file and reports the rules that have no effect on a working
+// tree, without modifying the file. See IgnoreFile.FindDeadRules.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to check.
+//   - tree: The directory the ignore file applies to, e.g. os.DirFS(".").
+//
+// Returns a slice of DeadRule and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - The tree cannot be walked
+//
+// Example:
+//
+//	deadRules, err := service.FindDeadRules(".gitignore", os.DirFS("."))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) FindDeadRules(path string, tree fs.FS) ([]DeadRule, error) {
+	var ignoreFile IgnoreFile
+	if err := s.load(path, &ignoreFile); err != nil {
+		return nil, err
+	}
+
+	return ignoreFile.FindDeadRules(tree)
+}
+
+// RemoveDeadRules removes the rules that have no effect on a working tree using an atomic
+// load-modify-save operation. See IgnoreFile.RemoveDeadRules.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to modify.
+//   - tree: The directory the ignore file applies to, e.g. os.DirFS(".").
+//
+// Returns a slice of Result describing each removal and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - The tree cannot be walked
+//   - The updated ignore file cannot be saved
+//
+// Example:
+//
+//	results, err := service.RemoveDeadRules(".gitignore", os.DirFS("."))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) RemoveDeadRules(path string, tree fs.FS) ([]Result, error) {
+	var results []Result
+
+	err := s.loadModifySave(path, func(f *IgnoreFile) error {
+		var err error
+		results, err = f.RemoveDeadRules(tree)
+		return err
+	})
+
+	return results, err
+}
+
+// MARK: Ecosystems
+
+// SuggestEcosystemRules loads an ignore file, detects the ecosystems of the directory it applies
+// to, and reports their standard rules that the file does not contain, without modifying the
+// file. See IgnoreFile.SuggestEcosystemRules.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to check.
+//   - tree: The directory the ignore file applies to, e.g. os.DirFS(".").
+//
+// Returns a slice of RuleSuggestion and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - The directory cannot be read
+//
+// Example:
+//
+//	suggestions, err := service.SuggestEcosystemRules(".gitignore", os.DirFS("."))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, suggestion := range suggestions {
+//	    fmt.Println(suggestion.Log())
+//	}
+func (s *Service) SuggestEcosystemRules(path string, tree fs.FS) ([]RuleSuggestion, error) {
+	var ignoreFile IgnoreFile
+	if err := s.load(path, &ignoreFile); err != nil {
+		return nil, err
+	}
+
+	return ignoreFile.SuggestEcosystemRules(tree)
+}
+
+// ApplySuggestions adds suggested rules to an ignore file using an atomic load-modify-save
+// operation. See IgnoreFile.ApplySuggestions.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to modify.
+//   - suggestions: The rules to add, e.g. from SuggestEcosystemRules.
+//
+// Returns a slice of Result describing every change and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - A suggested rule conflicts with an existing rule (see IgnoreFile.ApplySuggestions)
+//   - The updated ignore file cannot be saved
+//
+// Example:
+//
+//	results, err := service.ApplySuggestions(".gitignore", suggestions)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) ApplySuggestions(path string, suggestions []RuleSuggestion) ([]Result, error) {
+	var results []Result
+
+	err := s.loadModifySave(path, func(f *IgnoreFile) error {
+		var err error
+		results, err = f.ApplySuggestions(suggestions)
+		return err
+	})
+
+	return results, err
+}
+
+// MARK: Fixers
+
+// AutoFix automatically resolves conflicts in an ignore file using an atomic load-modify-save operation.
+// The method loads the ignore file from the specified path, runs multiple passes of conflict detection
+// and resolution, then saves the updated file.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to analyze and fix.
+//   - maxPasses: The maximum number of conflict resolution passes to attempt. Each pass finds all
+//     current conflicts and attempts to fix them before checking again. The method will stop early
+//     if no conflicts are found in a given pass.
+//
+// Returns a slice of Result containing descriptions of all fixes applied and an error.
+// The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - Any conflict resolution attempt fails
+//   - The updated ignore file cannot be saved
+//
+// Example:
+//
+//	fixes, err := service.AutoFix(".gitignore", 5)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	if len(fixes) > 0 {
+//	    fmt.Printf("Applied %d fixes:\n", len(fixes))
+//	    for _, fix := range fixes {
+//	        fmt.Printf("- %s\n", fix.Log())
+//	    }
+//	} else {
+//	    fmt.Println("No conflicts found")
+//	}
+func (s *Service) AutoFix(path string, maxPasses int) ([]Result, error) {
+	var fixes []Result
+
+	err := s.loadModifySave(path, func(f *IgnoreFile) error {
+		var err error
+		fixes, err = f.FixConflicts(maxPasses)
+		return err
+	})
+
+	return fixes, err
+}
+
+// Minimize rewrites an ignore file into a smaller rule set that ignores the same paths using an
+// atomic load-modify-save operation. See IgnoreFile.Minimize.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to minimize.
+//
+// Returns a slice of Result describing every change and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//   - A merged rule cannot be created
+//   - The updated ignore file cannot be saved
+//
+// Example:
+//
+//	results, err := service.Minimize(".gitignore")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) Minimize(path string) ([]Result, error) {
+	var results []Result
+
+	err := s.loadModifySave(path, func(f *IgnoreFile) error {
+		var err error
+		results, err = f.Minimize()
+		return err
+	})
+
+	return results, err
+}
+
+// Format rewrites an ignore file into canonical form using an atomic load-modify-save operation.
+// See IgnoreFile.Format.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to format.
+//
+// Returns an error if the ignore file cannot be loaded or saved.
+//
+// Example:
+//
+//	if err := service.Format(".gitignore"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Service) Format(path string) error {
+	return s.loadModifySave(path, func(f *IgnoreFile) error {
+		f.Format()
+		return nil
+	})
+}
+
+// MARK: Analyzers
+
+// AnalyzeConflicts loads an ignore file and returns all detected conflicts without making any modifications.
+// This method provides a read-only analysis of rule conflicts, useful for inspection and reporting
+// before deciding whether to apply automatic fixes.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to analyze.
+//
+// Returns a slice of Conflict containing all detected conflicts and an error.
+// The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//
+// Unlike AutoFix, this method performs no modifications to the file - it only reads and analyzes.
+// The conflicts are detected using comprehensive pairwise comparison of all rules, considering
+// rule order and any intervening rules between each pair.
+//
+// Conflict types that may be detected:
+//   - SEMANTIC_CONFLICT: Rules that contradict each other in meaning
+//   - REDUNDANT_RULE: Rules that duplicate existing functionality
+//   - UNREACHABLE_RULE: Rules that can never be triggered due to earlier rules
+//   - INEFFECTIVE_RULE: Rules that would be more effective in a different position
+//
+// Example:
+//
+//	conflicts, err := service.AnalyzeConflicts(".gitignore")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	if len(conflicts) > 0 {
+//	    fmt.Printf("Found %d conflicts:\n", len(conflicts))
+//	    for _, conflict := range conflicts {
+//	        fmt.Printf("- %s\n", conflict)
+//	    }
+//	} else {
+//	    fmt.Println("No conflicts detected")
+//	}
+func (s *Service) AnalyzeConflicts(path string) ([]Conflict, error) {
+	var ignoreFile IgnoreFile
+	if err := s.load(path, &ignoreFile); err != nil {
+		return nil, err
+	}
+
+	return ignoreFile.FindConflicts(), nil
+}
+
+// AnalyzeConflictsSARIF loads an ignore file and reports its conflicts as a SARIF 2.1.0 log, for
+// code scanning services that show findings inline on pull requests. Results are located at the
+// path as given, so pass it relative to the root of the repository. See ConflictsToSARIF for the
+// structure of the log. This method performs no modifications.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to analyze.
+//
+// Returns the SARIF log as JSON, the number of conflicts it reports, and an error if the file
+// cannot be loaded or the log cannot be encoded.
+//
+// Example:
+//
+//	report, found, err := service.AnalyzeConflictsSARIF(".gitignore")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	os.WriteFile("gignore.sarif", report, 0o644)
+//	if found > 0 {
+//	    os.Exit(1)
+//	}
+func (s *Service) AnalyzeConflictsSARIF(path string) ([]byte, int, error) {
+	var ignoreFile IgnoreFile
+	if err := s.load(path, &ignoreFile); err != nil {
+		return nil, 0, err
+	}
+
+	conflicts := ignoreFile.FindConflicts()
+	report, err := ConflictsToSARIF(ignoreFile, conflicts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return report, len(conflicts), nil
+}
+
+// CheckIgnore loads an ignore file and reports which rule, if any, decides whether each of the
+// given paths is ignored, mirroring `git check-ignore`. This method performs no modifications.
+//
+// Parameters:
+//   - path: The file system path to the ignore file to check against.
+//   - targets: The paths to check, relative to the directory of the ignore file. A trailing "/"
+//     marks a directory.
+//
+// Returns one Match per target, in the order given, and an error. The error will be non-nil if:
+//   - The ignore file cannot be loaded from the specified path
+//
+// Each Match has its Source set to path, so Match.Log produces `git check-ignore -v` output.
+//
+// Example:
+//
+//	matches, err := service.CheckIgnore(".gitignore", "debug.log", "build/")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, match := range matches {
+//	    if match.Ignored() {
+//	        fmt.Println(match.Log())
+//	    }
+//	}
+func (s *Service) CheckIgnore(path string, targets ...string) ([]Match, error) {
+	var ignoreFile IgnoreFile
+	if err := s.load(path, &ignoreFile); err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0, len(targets))
+	for _, target := range targets {
+		match := ignoreFile.Match(target)
+		match.Source = path
+
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
+
+// Loads an ignore file and records its path, so positions name the file with any repository
+func (s *Service) load(path string, ignoreFile *IgnoreFile) error {
+	if err := s.repo.Load(path, ignoreFile); err != nil {
+		return err
+	}
+
+	ignoreFile.SetSource(path)
+
+	return nil
 }
 
 // Helper to reduce duplication
 func (s *Service) loadModifySave(path string, modify func(*IgnoreFile) error) error {
 	var ignoreFile IgnoreFile
 
-	err := s.repo.Load(path, &ignoreFile)
+	err := s.load(path, &ignoreFile)
 	if err != nil {
 		return err
 	}
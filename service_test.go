@@ -1,8 +1,10 @@
 package gignore
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 type FakeRepository struct {
@@ -138,6 +140,8 @@ func TestServiceInit(t *testing.T) {
 	tests := []struct {
 		name         string
 		path         string
+		templates    []string
+		expected     string
 		errorMessage string
 	}{
 		{
@@ -145,6 +149,19 @@ func TestServiceInit(t *testing.T) {
 			path:         ".gitignore",
 			errorMessage: "",
 		},
+		{
+			name:         "Pass-Templates",
+			path:         ".gitignore",
+			templates:    []string{"rust", "macos"},
+			expected:     "# Rust\n*.pdb\n*.rs.bk\ntarget/\n\n# macOS\n.AppleDouble\n.DS_Store",
+			errorMessage: "",
+		},
+		{
+			name:         "Fail-UnknownTemplate",
+			path:         ".gitignore",
+			templates:    []string{"cobol"},
+			errorMessage: unknownTemplateError.Error(),
+		},
 	}
 
 	for _, tc := range tests {
@@ -152,7 +169,16 @@ func TestServiceInit(t *testing.T) {
 		svc := NewService(&repo)
 
 		t.Run(tc.name, func(t *testing.T) {
-			err := svc.Init(tc.path)
+			err := svc.Init(tc.path, tc.templates...)
+
+			expected := tc.expected
+			if len(tc.templates) > 0 && tc.errorMessage == "" {
+				expected = templateHeader(t, EmbeddedTemplates(), tc.templates...) + "\n" + tc.expected
+			}
+
+			if !strings.HasPrefix(repo.files[tc.path], expected) {
+				t.Errorf("expected file to start with %q, got %q", expected, repo.files[tc.path])
+			}
 
 			var errMsg string
 			if err != nil {
@@ -1074,3 +1100,498 @@ func TestServiceAutoFix(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceBatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		initRepo     bool
+		ignore       IgnoreFile
+		operations   []Operation
+		present      []Ruler
+		absent       []Ruler
+		errorMessage string
+	}{
+		{
+			name:     "Pass",
+			path:     ".gitignore",
+			initRepo: true,
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					FileRule{path: "todo.md", act: INCLUDE},
+				},
+			},
+			operations: []Operation{
+				AddExtensionOperation("log", INCLUDE),
+				AddGlobOperation("temp*.txt", INCLUDE),
+				DeleteFileOperation("todo.md", INCLUDE),
+			},
+			present: []Ruler{
+				ExtensionRule{ext: "log", act: INCLUDE},
+				GlobRule{pattern: "temp*.txt", act: INCLUDE},
+			},
+			absent: []Ruler{
+				FileRule{path: "todo.md", act: INCLUDE},
+			},
+			errorMessage: "",
+		},
+		{
+			name:     "Fail-NothingSaved",
+			path:     ".gitignore",
+			initRepo: true,
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					ExtensionRule{ext: "txt", act: INCLUDE},
+				},
+			},
+			operations: []Operation{
+				AddExtensionOperation("log", INCLUDE),
+				AddFileOperation("todo.txt", INCLUDE),
+			},
+			present: []Ruler{
+				ExtensionRule{ext: "txt", act: INCLUDE},
+			},
+			absent: []Ruler{
+				ExtensionRule{ext: "log", act: INCLUDE},
+			},
+			errorMessage: "operation 1: " + unreachableRuleError.Error(),
+		},
+		{
+			name:         "Fail-FileNotFound",
+			path:         ".gitignore",
+			initRepo:     false,
+			ignore:       IgnoreFile{},
+			operations:   []Operation{AddExtensionOperation("log", INCLUDE)},
+			errorMessage: fileReadError.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := NewFakeRepository()
+			svc := NewService(&repo)
+
+			if tc.initRepo {
+				repo.Save(tc.path, &tc.ignore)
+			}
+
+			_, err := svc.Batch(tc.path, tc.operations...)
+			checkErrors(tc.errorMessage, err, t)
+
+			if !tc.initRepo {
+				return
+			}
+
+			for _, rule := range tc.present {
+				checkRuleExists(&repo, tc.path, rule, t)
+			}
+
+			for _, rule := range tc.absent {
+				checkRuleDoesNotExist(&repo, tc.path, rule, t)
+			}
+		})
+	}
+}
+
+func TestServiceEnsure(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		initRepo     bool
+		ignore       IgnoreFile
+		present      string
+		absent       string
+		changed      bool
+		errorMessage string
+	}{
+		{
+			name:     "Pass-Changed",
+			path:     ".gitignore",
+			initRepo: true,
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					FileRule{path: "todo.md", act: INCLUDE},
+				},
+			},
+			present:      "*.log",
+			absent:       "todo.md",
+			changed:      true,
+			errorMessage: "",
+		},
+		{
+			name:     "Pass-NoOp",
+			path:     ".gitignore",
+			initRepo: true,
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					ExtensionRule{ext: "log", act: INCLUDE},
+				},
+			},
+			present:      "*.log",
+			absent:       "todo.md",
+			changed:      false,
+			errorMessage: "",
+		},
+		{
+			name:         "Fail-FileNotFound",
+			path:         ".gitignore",
+			initRepo:     false,
+			ignore:       IgnoreFile{},
+			present:      "*.log",
+			absent:       "todo.md",
+			errorMessage: fileReadError.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := NewFakeRepository()
+			svc := NewService(&repo)
+
+			if tc.initRepo {
+				repo.Save(tc.path, &tc.ignore)
+			}
+
+			results, err := svc.EnsurePresent(tc.path, tc.present)
+			checkErrors(tc.errorMessage, err, t)
+			if tc.errorMessage != "" {
+				return
+			}
+
+			result, err := svc.EnsureAbsent(tc.path, tc.absent)
+			checkErrors(tc.errorMessage, err, t)
+
+			if results[0].Changed() != tc.changed || result.Changed() != tc.changed {
+				t.Errorf("expected changed to be %t", tc.changed)
+			}
+
+			present, _ := parseRule(tc.present)
+			absent, _ := parseRule(tc.absent)
+			checkRuleExists(&repo, tc.path, present, t)
+			checkRuleDoesNotExist(&repo, tc.path, absent, t)
+		})
+	}
+}
+
+func TestServiceReconcile(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		initRepo     bool
+		ignore       IgnoreFile
+		desired      DesiredState
+		present      []Ruler
+		absent       []Ruler
+		errorMessage string
+	}{
+		{
+			name:     "Pass",
+			path:     ".gitignore",
+			initRepo: true,
+			ignore: IgnoreFile{
+				rules: []Ruler{
+					FileRule{path: "todo.md", act: INCLUDE},
+				},
+			},
+			desired: NewDesiredState(true,
+				ExtensionRule{ext: "log", act: INCLUDE},
+			),
+			present: []Ruler{
+				ExtensionRule{ext: "log", act: INCLUDE},
+			},
+			absent: []Ruler{
+				FileRule{path: "todo.md", act: INCLUDE},
+			},
+			errorMessage: "",
+		},
+		{
+			name:         "Fail-FileNotFound",
+			path:         ".gitignore",
+			initRepo:     false,
+			ignore:       IgnoreFile{},
+			desired:      NewDesiredState(false),
+			errorMessage: fileReadError.Error(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := NewFakeRepository()
+			svc := NewService(&repo)
+
+			if tc.initRepo {
+				repo.Save(tc.path, &tc.ignore)
+			}
+
+			_, err := svc.PlanReconcile(tc.path, tc.desired)
+			checkErrors(tc.errorMessage, err, t)
+
+			// Planning must not modify the file
+			for _, rule := range tc.absent {
+				checkRuleExists(&repo, tc.path, rule, t)
+			}
+
+			_, err = svc.Reconcile(tc.path, tc.desired)
+			checkErrors(tc.errorMessage, err, t)
+			if tc.errorMessage != "" {
+				return
+			}
+
+			for _, rule := range tc.present {
+				checkRuleExists(&repo, tc.path, rule, t)
+			}
+
+			for _, rule := range tc.absent {
+				checkRuleDoesNotExist(&repo, tc.path, rule, t)
+			}
+		})
+	}
+}
+
+func TestServiceEnableManagedBlock(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+
+	repo.files[".gitignore"] = "*.log"
+
+	err := svc.EnableManagedBlock(".gitignore")
+	checkErrors("", err, t)
+
+	repo.files[".gitignore"] = "todo.md\n" + repo.files[".gitignore"]
+
+	_, err = svc.AddDirectoryRule(".gitignore", "build", RECURSIVE, INCLUDE)
+	checkErrors("", err, t)
+
+	expected := "todo.md\n" + MANAGED_BLOCK_BEGIN + "\n*.log\nbuild/**\n" + MANAGED_BLOCK_END
+	if repo.files[".gitignore"] != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, repo.files[".gitignore"])
+	}
+}
+
+func TestServiceSections(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+	repo.files[".gitignore"] = "# Go\n*.test"
+
+	_, err := svc.AddRuleToSection(".gitignore", "IDE", ".idea/")
+	checkErrors("", err, t)
+
+	_, err = svc.AddFileRule(".gitignore", "todo.md", INCLUDE)
+	checkErrors("", err, t)
+
+	_, err = svc.MoveRuleToSection(".gitignore", "todo.md", "Go")
+	checkErrors("", err, t)
+
+	expected := "# Go\n*.test\ntodo.md\n\n# IDE\n.idea/"
+	if repo.files[".gitignore"] != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, repo.files[".gitignore"])
+	}
+}
+
+func TestServiceAddToSection(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+	repo.files[".gitignore"] = "# Go\n*.test"
+
+	rule, err := NewFileRule("draft*.md", INCLUDE)
+	checkErrors("", err, t)
+
+	results, err := svc.AddToSection(".gitignore", "Drafts", rule)
+	checkErrors("", err, t)
+
+	if len(results) != 1 || RuleKind(results[0].Rule) != FILE_KIND {
+		t.Errorf("expected the rule to be added as a file rule, got %v", results)
+	}
+
+	expected := "# Go\n*.test\n\n# Drafts\ndraft*.md"
+	if repo.files[".gitignore"] != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, repo.files[".gitignore"])
+	}
+}
+
+func TestServiceCheckIgnore(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+	repo.files[".gitignore"] = "*.log\n!keep.log"
+
+	matches, err := svc.CheckIgnore(".gitignore", "debug.log", "keep.log", "main.go")
+	checkErrors("", err, t)
+
+	expected := []string{".gitignore:1:*.log\tdebug.log", ".gitignore:2:!keep.log\tkeep.log", "::\tmain.go"}
+	for idx, match := range matches {
+		if match.Log() != expected[idx] {
+			t.Errorf("expected %q, got %q", expected[idx], match.Log())
+		}
+	}
+}
+
+func TestServiceAnalyzeConflictsSuppressed(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+	repo.files[".gitignore"] = "*.log\n# gignore:ignore-next-line REDUNDANT_RULE\n*.log"
+
+	conflicts, err := svc.AnalyzeConflicts(".gitignore")
+	checkErrors("", err, t)
+
+	if len(conflicts) != 0 {
+		t.Errorf("expected suppressed conflicts to be skipped, got %v", conflicts)
+	}
+}
+
+func TestServiceDeadRules(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+	repo.files[".gitignore"] = "*.log\n*.pyc"
+
+	tree := fstest.MapFS{"debug.log": {}}
+
+	deadRules, err := svc.FindDeadRules(".gitignore", tree)
+	checkErrors("", err, t)
+
+	if len(deadRules) != 1 || deadRules[0].Log() != "UNMATCHED_RULE: Rule '*.pyc'" {
+		t.Fatalf("expected *.pyc to be reported, got %v", deadRules)
+	}
+
+	_, err = svc.RemoveDeadRules(".gitignore", tree)
+	checkErrors("", err, t)
+
+	if repo.files[".gitignore"] != "*.log" {
+		t.Errorf("expected *.pyc to be removed, got %q", repo.files[".gitignore"])
+	}
+}
+
+func TestServiceEcosystemSuggestions(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+	repo.files[".gitignore"] = "bin/\nobj/\n*.user\n*.suo\n*.rsuser\nTestResults/\n*.nupkg"
+
+	tree := fstest.MapFS{"App.csproj": {}}
+
+	suggestions, err := svc.SuggestEcosystemRules(".gitignore", tree)
+	checkErrors("", err, t)
+
+	if len(suggestions) != 1 || suggestions[0].Log() != "MISSING: Rule '.vs/', Ecosystem: DOTNET" {
+		t.Fatalf("expected .vs/ to be suggested, got %v", suggestions)
+	}
+
+	_, err = svc.ApplySuggestions(".gitignore", suggestions)
+	checkErrors("", err, t)
+
+	if expected := "bin/\nobj/\n*.user\n*.suo\n*.rsuser\nTestResults/\n*.nupkg\n\n# .NET\n.vs/"; repo.files[".gitignore"] != expected {
+		t.Errorf("expected %q, got %q", expected, repo.files[".gitignore"])
+	}
+}
+
+func TestServiceTemplateSource(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+	source := NewMapTemplateSource(map[string]string{
+		"base":     "*.secret\n",
+		"services": ".cache/\n",
+	})
+
+	checkErrors("", svc.InitFromSource(".gitignore", source, "base"), t)
+
+	_, err := svc.AddTemplate(".gitignore", source, "services")
+	checkErrors("", err, t)
+
+	// Both templates are recorded, including the one added later
+	expected := templateHeader(t, source, "base", "services") + "\n# base\n*.secret\n\n# services\n.cache/"
+	if repo.files[".gitignore"] != expected {
+		t.Errorf("expected %q, got %q", expected, repo.files[".gitignore"])
+	}
+}
+
+func TestServiceFormat(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+	repo.files[".gitignore"] = "build//\n*.log\n!keep.log\n*.bak"
+
+	checkErrors("", svc.Format(".gitignore"), t)
+
+	if expected := "*.log\nbuild/\n!keep.log\n*.bak"; repo.files[".gitignore"] != expected {
+		t.Errorf("expected %q, got %q", expected, repo.files[".gitignore"])
+	}
+}
+
+func TestServiceMinimize(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+	repo.files[".gitignore"] = "logs/**\nlogs/*.log\n*.pyc\n*.pyo"
+
+	results, err := svc.Minimize(".gitignore")
+	checkErrors("", err, t)
+
+	if len(results) != 4 {
+		t.Errorf("expected 4 results, got %v", results)
+	}
+
+	if expected := "logs/**\n*.py[co]"; repo.files[".gitignore"] != expected {
+		t.Errorf("expected %q, got %q", expected, repo.files[".gitignore"])
+	}
+}
+
+func TestServiceConflictPositions(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+	repo.files["services/api/.gitignore"] = "build/**\n*.log\nbuild/"
+
+	conflicts, err := svc.AnalyzeConflicts("services/api/.gitignore")
+	checkErrors("", err, t)
+
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %v", conflicts)
+	}
+
+	if position := conflicts[0].RightPosition.String(); position != "services/api/.gitignore:3" {
+		t.Errorf("expected services/api/.gitignore:3, got %q", position)
+	}
+}
+
+func TestServiceAnalyzeConflictsSARIF(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+	repo.files[".gitignore"] = "build/**\n*.log\nbuild/\n*.log"
+
+	report, found, err := svc.AnalyzeConflictsSARIF(".gitignore")
+	checkErrors("", err, t)
+
+	if found != 2 {
+		t.Errorf("expected 2 conflicts, got %d", found)
+	}
+
+	var log sarifLog
+	checkErrors("", json.Unmarshal(report, &log), t)
+
+	if results := log.Runs[0].Results; len(results) != found {
+		t.Errorf("expected a result per conflict, got %+v", results)
+	}
+
+	if _, _, err := svc.AnalyzeConflictsSARIF("missing/.gitignore"); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
+
+func TestServicePolicy(t *testing.T) {
+	repo := NewFakeRepository()
+	svc := NewService(&repo)
+	repo.files["source/.gitignore"] = "# Logs\n*.log\n!keep.log"
+
+	policy, err := svc.ExportPolicy("source/.gitignore")
+	checkErrors("", err, t)
+
+	checkErrors("", svc.ImportPolicy("target/.gitignore", policy), t)
+
+	if repo.files["target/.gitignore"] != repo.files["source/.gitignore"] {
+		t.Errorf("expected %q, got %q", repo.files["source/.gitignore"], repo.files["target/.gitignore"])
+	}
+
+	policy.Rules[0].Kind = "regex"
+	if err := svc.ImportPolicy("target/.gitignore", policy); err == nil {
+		t.Errorf("expected an invalid policy to be rejected")
+	}
+
+	if repo.files["target/.gitignore"] != repo.files["source/.gitignore"] {
+		t.Errorf("expected the file to be left alone, got %q", repo.files["target/.gitignore"])
+	}
+}
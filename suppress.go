@@ -0,0 +1,140 @@
+package gignore
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Suppression directives are comments that silence conflict and lint findings that are
+// intentional:
+//
+//	# gignore:disable UNREACHABLE_RULE
+//	# gignore:ignore-next-line REDUNDANT_RULE
+//
+// gignore:disable applies to the whole file, gignore:ignore-next-line only to the rule that
+// directly follows the comment block it is part of. Both accept any number of conflict types
+// or lint check IDs, separated by spaces or commas. Without IDs they silence every finding.
+
+// MARK: Directives
+const (
+	IGNORE_NEXT_LINE_DIRECTIVE = "gignore:ignore-next-line"
+	DISABLE_DIRECTIVE          = "gignore:disable"
+)
+
+// Returns the directive and IDs of a suppression comment
+func parseDirective(line string) (string, []string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "#") {
+		return "", nil, false
+	}
+
+	text := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+	for _, directive := range []string{IGNORE_NEXT_LINE_DIRECTIVE, DISABLE_DIRECTIVE} {
+		if text != directive && !strings.HasPrefix(text, directive+" ") {
+			continue
+		}
+
+		ids := strings.FieldsFunc(strings.TrimPrefix(text, directive), func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+
+		return directive, ids, true
+	}
+
+	return "", nil, false
+}
+
+func isDirective(line string) bool {
+	_, _, ok := parseDirective(line)
+	return ok
+}
+
+// MARK: Suppressions
+type suppressions struct {
+	all bool
+	ids map[string]bool
+}
+
+// Collects the IDs of every directive of the given kind in lines
+func collectSuppressions(lines []string, directive string) suppressions {
+	var s suppressions
+
+	for _, line := range lines {
+		found, ids, ok := parseDirective(line)
+		if !ok || found != directive {
+			continue
+		}
+
+		if len(ids) == 0 {
+			s.all = true
+		}
+
+		for _, id := range ids {
+			if s.ids == nil {
+				s.ids = make(map[string]bool)
+			}
+			s.ids[normalizeSuppressionID(id)] = true
+		}
+	}
+
+	return s
+}
+
+// Conflict types are suppressed by the name they are reported under, but the misspelled value of
+// REDUNDANT_RULE is accepted too
+func normalizeSuppressionID(id string) string {
+	if conflictType, err := conflictTypeFromName(id); err == nil {
+		return conflictTypeName(conflictType)
+	}
+
+	return id
+}
+
+func (s suppressions) covers(id string) bool {
+	return s.all || s.ids[id]
+}
+
+func (s suppressions) empty() bool {
+	return !s.all && len(s.ids) == 0
+}
+
+// Returns the gignore:disable directives found anywhere in the file
+func (f IgnoreFile) fileSuppressions() suppressions {
+	lines := append([]string{}, f.directives...)
+
+	for _, meta := range f.allMeta() {
+		lines = append(lines, meta.comments...)
+	}
+
+	return collectSuppressions(lines, DISABLE_DIRECTIVE)
+}
+
+// Returns the gignore:ignore-next-line directives placed directly above any copy of a rule
+func (f IgnoreFile) ruleSuppressions(rule Ruler) suppressions {
+	var lines []string
+
+	metas := f.allMeta()
+	for idx, existing := range f.allRules() {
+		if rulesEqual(existing, rule) {
+			lines = append(lines, metas[idx].comments...)
+		}
+	}
+
+	return collectSuppressions(lines, IGNORE_NEXT_LINE_DIRECTIVE)
+}
+
+// Reports whether a conflict is silenced by a directive on the file or on either rule
+func (f IgnoreFile) isSuppressed(conflict Conflict, file suppressions) bool {
+	id := conflictTypeName(conflict.ConflictType)
+
+	return file.covers(id) ||
+		f.ruleSuppressions(conflict.Left).covers(id) ||
+		f.ruleSuppressions(conflict.Right).covers(id)
+}
+
+// IsSuppressed reports whether a rule has a gignore:ignore-next-line directive. FixConflicts
+// never moves or deletes a rule to fix a conflict whose type the directive covers, but still
+// fixes conflicts of other types.
+func (f IgnoreFile) IsSuppressed(rule Ruler) bool {
+	return !f.ruleSuppressions(rule).empty()
+}
@@ -0,0 +1,221 @@
+package gignore
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDirective(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		directive string
+		ids       []string
+		ok        bool
+	}{
+		{name: "Pass-NextLine", line: "# gignore:ignore-next-line REDUNDANT_RULE", directive: IGNORE_NEXT_LINE_DIRECTIVE, ids: []string{"REDUNDANT_RULE"}, ok: true},
+		{name: "Pass-DisableMany", line: "#gignore:disable UNREACHABLE_RULE, TRAILING_WHITESPACE", directive: DISABLE_DIRECTIVE, ids: []string{"UNREACHABLE_RULE", "TRAILING_WHITESPACE"}, ok: true},
+		{name: "Pass-NoIDs", line: "# gignore:disable", directive: DISABLE_DIRECTIVE, ids: []string{}, ok: true},
+		{name: "Fail-Comment", line: "# gignore:disabled for now", ok: false},
+		{name: "Fail-Rule", line: "gignore:disable", ok: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			directive, ids, ok := parseDirective(tc.line)
+			if ok != tc.ok {
+				t.Fatalf("expected ok to be %t, got %t", tc.ok, ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			if directive != tc.directive || len(ids) != len(tc.ids) || (len(ids) > 0 && !reflect.DeepEqual(ids, tc.ids)) {
+				t.Errorf("expected %s %v, got %s %v", tc.directive, tc.ids, directive, ids)
+			}
+		})
+	}
+}
+
+func TestFindConflictsSuppressed(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		conflicts []ConflictType
+	}{
+		{
+			name:      "Pass-Unsuppressed",
+			content:   "*.log\n*.log\nbuild/**\nbuild/",
+			conflicts: []ConflictType{REDUNDANT_RULE, UNREACHABLE_RULE},
+		},
+		{
+			name:      "Pass-IgnoreNextLine",
+			content:   "*.log\n# kept on purpose\n# gignore:ignore-next-line REDUNDANT_RULE\n*.log\nbuild/**\nbuild/",
+			conflicts: []ConflictType{UNREACHABLE_RULE},
+		},
+		{
+			name:      "Pass-IgnoreNextLineOtherType",
+			content:   "*.log\n# gignore:ignore-next-line UNREACHABLE_RULE\n*.log",
+			conflicts: []ConflictType{REDUNDANT_RULE},
+		},
+		{
+			name:      "Pass-IgnoreNextLineAll",
+			content:   "build/**\n# gignore:ignore-next-line\nbuild/",
+			conflicts: []ConflictType{},
+		},
+		{
+			name:      "Pass-FileLevel",
+			content:   "# gignore:disable UNREACHABLE_RULE\n\n*.log\n*.log\nbuild/**\nbuild/",
+			conflicts: []ConflictType{REDUNDANT_RULE},
+		},
+		{
+			name:      "Pass-IgnoreNextLineMisspelled",
+			content:   "*.log\n# gignore:ignore-next-line REDUNANT_RULE\n*.log",
+			conflicts: []ConflictType{},
+		},
+		{
+			name:      "Pass-NotDirectlyAbove",
+			content:   "*.log\n# gignore:ignore-next-line REDUNDANT_RULE\n\n*.log",
+			conflicts: []ConflictType{REDUNDANT_RULE},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore := NewIgnoreFile()
+			if err := Parse(tc.content, &ignore); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			conflicts := ignore.FindConflicts()
+			if len(conflicts) != len(tc.conflicts) {
+				t.Fatalf("expected %d conflicts, got %d: %v", len(tc.conflicts), len(conflicts), conflicts)
+			}
+
+			for idx, conflict := range conflicts {
+				if conflict.ConflictType != tc.conflicts[idx] {
+					t.Errorf("expected %s, got %s", tc.conflicts[idx], conflict.ConflictType)
+				}
+			}
+		})
+	}
+}
+
+func TestFixConflictsSkipsSuppressedRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		results  []ActionResult
+		expected string
+	}{
+		{
+			name:     "Pass-ConflictTypeSuppressed",
+			content:  "*.log\n# gignore:ignore-next-line REDUNDANT_RULE\n*.log",
+			results:  []ActionResult{},
+			expected: "*.log\n# gignore:ignore-next-line REDUNDANT_RULE\n*.log",
+		},
+		{
+			name:     "Pass-OtherConflictTypeFixed",
+			content:  "build/**\n!build/keep.txt\n*.log\n# gignore:ignore-next-line SEMANTIC_CONFLICT\n*.log",
+			results:  []ActionResult{REMOVED},
+			expected: "build/**\n!build/keep.txt\n# gignore:ignore-next-line SEMANTIC_CONFLICT\n*.log",
+		},
+		{
+			name:     "Pass-UnreachableFixedDespiteRedundantSuppression",
+			content:  "*.log\n# gignore:ignore-next-line REDUNDANT_RULE\ndebug.log",
+			results:  []ActionResult{REMOVED},
+			expected: "*.log",
+		},
+		{
+			name:     "Pass-FileSuppressionOfOtherType",
+			content:  "# gignore:disable UNREACHABLE_RULE\n\n*.log\n*.log",
+			results:  []ActionResult{REMOVED},
+			expected: "# gignore:disable UNREACHABLE_RULE\n\n*.log",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore := NewIgnoreFile()
+			Parse(tc.content, &ignore)
+
+			results, err := ignore.FixConflicts(20)
+			checkErrors("", err, t)
+
+			if len(results) != len(tc.results) {
+				t.Fatalf("expected %d results, got %d: %v", len(tc.results), len(results), results)
+			}
+
+			for idx, result := range results {
+				if result.Result != tc.results[idx] {
+					t.Errorf("expected result %d to be %s, got %s", idx, tc.results[idx], result.Result)
+				}
+			}
+
+			if rendered := Render(&ignore, RenderOptions{}); rendered != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, rendered)
+			}
+		})
+	}
+}
+
+func TestRenderComments(t *testing.T) {
+	content := `# gignore:disable UNREACHABLE_RULE
+
+# Go
+# Test binary, built with go test -c
+*.test
+build/**
+# gignore:ignore-next-line REDUNDANT_RULE
+build/**`
+
+	ignore := NewIgnoreFile()
+	Parse(content, &ignore)
+
+	if rendered := Render(&ignore, RenderOptions{}); rendered != content {
+		t.Errorf("expected:\n%s\ngot:\n%s", content, rendered)
+	}
+
+	// Comments travel with the rule they belong to
+	rule, _ := NewExtensionRule("test", INCLUDE)
+	target, _ := NewDirectoryRule("build", RECURSIVE, INCLUDE)
+	if _, err := ignore.MoveRule(rule, target, AFTER, REQUESTED); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := `# gignore:disable UNREACHABLE_RULE
+
+# Go
+build/**
+# Test binary, built with go test -c
+*.test
+# gignore:ignore-next-line REDUNDANT_RULE
+build/**`
+
+	if rendered := Render(&ignore, RenderOptions{}); rendered != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, rendered)
+	}
+}
+
+func TestLintSuppressed(t *testing.T) {
+	content := "# gignore:disable DOT_SLASH_PREFIX\n./build/\n# gignore:ignore-next-line TRAILING_WHITESPACE\n*.log \n*.tmp "
+
+	diagnostics := NewLinter().Lint(content)
+	if len(diagnostics) != 1 || diagnostics[0].Line != 5 {
+		t.Errorf("expected a single diagnostic on line 5, got %v", diagnostics)
+	}
+}
+
+func TestFixConflictsKeepsCommentsOfRemovedCopy(t *testing.T) {
+	ignore := NewIgnoreFile()
+	Parse("# Logs\n# Debug output\n*.log\n\n# Build\nbuild/\n*.log\n", &ignore)
+
+	_, err := ignore.FixConflicts(5)
+	checkErrors("", err, t)
+
+	expected := "# Build\nbuild/\n# Debug output\n*.log"
+	if rendered := Render(&ignore, RenderOptions{}); rendered != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, rendered)
+	}
+}
@@ -0,0 +1,97 @@
+package gignore
+
+import (
+	"embed"
+	"errors"
+	"path"
+	"strings"
+)
+
+var (
+	unknownTemplateError     = errors.New("unknown template")
+	invalidTemplateNameError = errors.New("template names cannot contain whitespace")
+)
+
+//go:embed templates/*.gitignore
+var embeddedTemplates embed.FS
+
+const templateExtension = ".gitignore"
+
+// Section names for the embedded templates, templates without one use their name
+var templateLabels = map[string]string{
+	"go":        "Go",
+	"node":      "Node",
+	"python":    "Python",
+	"java":      "Java",
+	"dotnet":    ".NET",
+	"rust":      "Rust",
+	"terraform": "Terraform",
+	"jetbrains": "JetBrains",
+	"vscode":    "VS Code",
+	"macos":     "macOS",
+	"linux":     "Linux",
+}
+
+// Returns the section name for a template, e.g. "VS Code" for "vscode" and "macOS" for "Global/macOS"
+func templateLabel(name string) string {
+	base := path.Base(name)
+	if label, ok := templateLabels[strings.ToLower(base)]; ok {
+		return label
+	}
+
+	return base
+}
+
+// TemplateNames returns the names of the embedded templates in lexical order: dotnet, go, java,
+// jetbrains, linux, macos, node, python, rust, terraform and vscode.
+func TemplateNames() []string {
+	names, _ := EmbeddedTemplates().Names()
+
+	return names
+}
+
+// LoadTemplate parses an embedded template into an IgnoreFile. Names are case-insensitive.
+//
+// Parameters:
+//   - name: The name of the template, e.g. "go" or "jetbrains". See TemplateNames.
+//
+// Returns the parsed IgnoreFile and an error if no template has the given name.
+func LoadTemplate(name string) (IgnoreFile, error) {
+	return EmbeddedTemplates().Template(name)
+}
+
+// ComposeTemplates combines embedded templates into a single IgnoreFile. Each template becomes a
+// section labelled after it (for example "Go" or "VS Code"). Duplicate and conflicting rules
+// across templates are resolved with FixConflicts, so a rule shared by two templates ends up in
+// the section of the last one, and a rule covered by a broader rule of another template is dropped.
+// The composed file is in the canonical form of Format.
+//
+// Parameters:
+//   - names: The names of the templates to compose, in order. See TemplateNames.
+//
+// Returns the composed IgnoreFile, the Results of conflict resolution, and an error if a
+// template is unknown or conflict resolution fails.
+//
+// Example:
+//
+//	ignoreFile, fixes, err := ComposeTemplates("go", "jetbrains", "macos")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, fix := range fixes {
+//	    fmt.Println(fix.Log())
+//	}
+func ComposeTemplates(names ...string) (IgnoreFile, []Result, error) {
+	return ComposeTemplatesFrom(EmbeddedTemplates(), names...)
+}
+
+// Appends the rules of a template to the end of the file under a single section, without conflict
+// checks. Every rule keeps the comments it has in the template
+func (f *IgnoreFile) appendTemplate(label string, template IgnoreFile) {
+	for idx, rule := range template.Rules() {
+		meta := template.metaAt(idx)
+
+		f.addRule(rule, ruleMeta{section: label, comments: append([]string{}, meta.comments...)})
+	}
+}
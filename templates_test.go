@@ -0,0 +1,101 @@
+package gignore
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTemplateNames(t *testing.T) {
+	expected := []string{"dotnet", "go", "java", "jetbrains", "linux", "macos", "node", "python", "rust", "terraform", "vscode"}
+
+	if names := TemplateNames(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	for _, name := range TemplateNames() {
+		t.Run(name, func(t *testing.T) {
+			template, err := LoadTemplate(name)
+			checkErrors("", err, t)
+
+			if len(template.Rules()) == 0 {
+				t.Errorf("expected template %s to contain rules", name)
+			}
+
+			if conflicts := template.FindConflicts(); len(conflicts) > 0 {
+				t.Errorf("expected template %s to be free of conflicts, got %v", name, conflicts)
+			}
+		})
+	}
+
+	if _, err := LoadTemplate("Go"); err != nil {
+		t.Errorf("expected template names to be case-insensitive, got %s", err.Error())
+	}
+
+	_, err := LoadTemplate("cobol")
+	checkErrors(unknownTemplateError.Error(), err, t)
+}
+
+func TestComposeTemplates(t *testing.T) {
+	composed, fixes, err := ComposeTemplates("go", "node", "python")
+	checkErrors("", err, t)
+
+	if conflicts := composed.FindConflicts(); len(conflicts) > 0 {
+		t.Errorf("expected no conflicts after composing, got %v", conflicts)
+	}
+
+	if sections := composed.Sections(); !reflect.DeepEqual(sections, []string{"Go", "Node", "Python"}) {
+		t.Errorf("expected a section per template, got %v", sections)
+	}
+
+	// .env is part of all three templates, only the copy in the last one is kept
+	envCopies := 0
+	for _, rule := range composed.Rules() {
+		if rule.Render() == ".env" {
+			envCopies++
+		}
+	}
+
+	if envCopies != 1 || composed.SectionOf(FileRule{path: ".env", act: INCLUDE}) != "Python" {
+		t.Errorf("expected one .env rule in the Python section, got %d in %q", envCopies, composed.SectionOf(FileRule{path: ".env", act: INCLUDE}))
+	}
+
+	removed := make([]string, 0)
+	for _, fix := range fixes {
+		if fix.Result == REMOVED {
+			removed = append(removed, fix.Rule.Render())
+		}
+	}
+
+	if expected := []string{".env", ".env", "dist/"}; !reflect.DeepEqual(removed, expected) {
+		t.Errorf("expected duplicates %v to be removed, got %v", expected, removed)
+	}
+
+	// The composed file round trips through the parser with its sections intact
+	reparsed := NewIgnoreFile()
+	rendered := Render(&composed, RenderOptions{})
+	checkErrors("", Parse(rendered, &reparsed), t)
+
+	if Render(&reparsed, RenderOptions{}) != rendered {
+		t.Errorf("expected composed file to round trip, got %q", Render(&reparsed, RenderOptions{}))
+	}
+
+	if formatted, err := Format(rendered, RenderOptions{}); err != nil || formatted != rendered {
+		t.Errorf("expected composed file to be formatted, got %q", formatted)
+	}
+
+	if expected := templateHeader(t, EmbeddedTemplates(), "go", "node", "python") + "\n# Go\n*.dll\n"; !strings.HasPrefix(rendered, expected) {
+		t.Errorf("expected composed file to start with %q, got %q", expected, rendered)
+	}
+
+	if records := composed.Templates(); len(records) != 3 || records[2].Name != "python" {
+		t.Errorf("expected a record per template, got %v", records)
+	}
+}
+
+func TestComposeTemplatesUnknown(t *testing.T) {
+	_, _, err := ComposeTemplates("go", "cobol")
+	checkErrors(unknownTemplateError.Error(), err, t)
+}
@@ -0,0 +1,331 @@
+package gignore
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// TemplateSource is a collection of ignore file templates, such as the embedded templates, a
+// local checkout of the github/gitignore collection or an organization's curated templates.
+type TemplateSource interface {
+	// Names returns the name of every template in lexical order.
+	Names() ([]string, error)
+	// Template parses the template with the given name. Names are case-insensitive, and a
+	// template in a subdirectory can also be found by its base name, e.g. "macOS" for
+	// "Global/macOS". Returns an error if no template has the given name.
+	Template(name string) (IgnoreFile, error)
+}
+
+// Returns the name of the template matching a name, comparing full names first and base names second
+func resolveTemplateName(names []string, name string) (string, bool) {
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return candidate, true
+		}
+	}
+
+	for _, candidate := range names {
+		if strings.EqualFold(path.Base(candidate), name) {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+// Returns the name the source knows a template by, or the name itself if it can't be resolved
+func resolvedTemplateName(source TemplateSource, name string) string {
+	if names, err := source.Names(); err == nil {
+		if resolved, ok := resolveTemplateName(names, name); ok {
+			return resolved
+		}
+	}
+
+	return name
+}
+
+func parseTemplate(content string) (IgnoreFile, error) {
+	template := NewIgnoreFile()
+	if err := Parse(content, &template); err != nil {
+		return IgnoreFile{}, err
+	}
+
+	return template, nil
+}
+
+// MARK: Sources
+
+type fsTemplateSource struct {
+	fsys fs.FS
+}
+
+// NewFSTemplateSource creates a TemplateSource from the .gitignore files of an fs.FS, including
+// the ones in subdirectories. A template is named after its path without the extension, e.g.
+// "Go" for "Go.gitignore" and "Global/macOS" for "Global/macOS.gitignore".
+//
+// Example:
+//
+//	//go:embed ignore-templates
+//	var templates embed.FS
+//
+//	sub, _ := fs.Sub(templates, "ignore-templates")
+//	source := NewFSTemplateSource(sub)
+func NewFSTemplateSource(fsys fs.FS) TemplateSource {
+	return fsTemplateSource{fsys: fsys}
+}
+
+// NewDirTemplateSource creates a TemplateSource from the .gitignore files of a local directory,
+// for example a checkout of https://github.com/github/gitignore. See NewFSTemplateSource.
+//
+// Example:
+//
+//	source := NewDirTemplateSource("/opt/gitignore")
+//	ignoreFile, fixes, err := ComposeTemplatesFrom(source, "Go", "macOS")
+func NewDirTemplateSource(dir string) TemplateSource {
+	return NewFSTemplateSource(os.DirFS(dir))
+}
+
+func (s fsTemplateSource) Names() ([]string, error) {
+	names := make([]string, 0)
+
+	err := fs.WalkDir(s.fsys, ".", func(entryPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if entryPath != "." && strings.HasPrefix(entry.Name(), ".") {
+				return fs.SkipDir // .git, .github and the like
+			}
+			return nil
+		}
+
+		if strings.HasSuffix(entryPath, templateExtension) && entry.Name() != templateExtension {
+			names = append(names, strings.TrimSuffix(entryPath, templateExtension))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (s fsTemplateSource) Template(name string) (IgnoreFile, error) {
+	names, err := s.Names()
+	if err != nil {
+		return IgnoreFile{}, err
+	}
+
+	resolved, ok := resolveTemplateName(names, name)
+	if !ok {
+		return IgnoreFile{}, unknownTemplateError
+	}
+
+	content, err := fs.ReadFile(s.fsys, resolved+templateExtension)
+	if err != nil {
+		return IgnoreFile{}, fileReadError
+	}
+
+	return parseTemplate(string(content))
+}
+
+type mapTemplateSource map[string]string
+
+// NewMapTemplateSource creates a TemplateSource from template contents keyed by name.
+//
+// Example:
+//
+//	source := NewMapTemplateSource(map[string]string{
+//	    "company": "*.secret\n.cache/\n",
+//	})
+func NewMapTemplateSource(templates map[string]string) TemplateSource {
+	source := make(mapTemplateSource, len(templates))
+	for name, content := range templates {
+		source[name] = content
+	}
+
+	return source
+}
+
+func (s mapTemplateSource) Names() ([]string, error) {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (s mapTemplateSource) Template(name string) (IgnoreFile, error) {
+	names, _ := s.Names()
+
+	resolved, ok := resolveTemplateName(names, name)
+	if !ok {
+		return IgnoreFile{}, unknownTemplateError
+	}
+
+	return parseTemplate(s[resolved])
+}
+
+// EmbeddedTemplates returns the templates embedded in gignore. See TemplateNames.
+func EmbeddedTemplates() TemplateSource {
+	sub, _ := fs.Sub(embeddedTemplates, "templates")
+
+	return NewFSTemplateSource(sub)
+}
+
+// MARK: Search
+
+// SearchTemplates returns the names of the templates in a source that contain a query,
+// ignoring case, in lexical order.
+//
+// Parameters:
+//   - source: The TemplateSource to search.
+//   - query: The text to look for, e.g. "java". An empty query matches every template.
+//
+// Returns the matching names and an error if the source cannot list its templates.
+//
+// Example:
+//
+//	names, err := SearchTemplates(NewDirTemplateSource("/opt/gitignore"), "python")
+//	// [Python community/Python/JupyterNotebooks]
+func SearchTemplates(source TemplateSource, query string) ([]string, error) {
+	names, err := source.Names()
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.ToLower(query)
+	matches := make([]string, 0)
+	for _, name := range names {
+		if strings.Contains(strings.ToLower(name), query) {
+			matches = append(matches, name)
+		}
+	}
+
+	return matches, nil
+}
+
+// MARK: Composition
+
+// ComposeTemplatesFrom combines templates from a TemplateSource into a single IgnoreFile, the
+// same way ComposeTemplates combines the embedded templates.
+//
+// Parameters:
+//   - source: The TemplateSource to load the templates from.
+//   - names: The names of the templates to compose, in order.
+//
+// Returns the composed IgnoreFile, the Results of conflict resolution, and an error if a
+// template cannot be loaded, its name contains whitespace, or conflict resolution fails.
+//
+// Example:
+//
+//	source := NewDirTemplateSource("/opt/company-templates")
+//	ignoreFile, fixes, err := ComposeTemplatesFrom(source, "base", "services")
+func ComposeTemplatesFrom(source TemplateSource, names ...string) (IgnoreFile, []Result, error) {
+	composed := NewIgnoreFile()
+
+	for _, name := range names {
+		template, err := source.Template(name)
+		if err != nil {
+			return IgnoreFile{}, make([]Result, 0), err
+		}
+
+		resolved := resolvedTemplateName(source, name)
+		if err := validateTemplateName(resolved); err != nil {
+			return IgnoreFile{}, make([]Result, 0), err
+		}
+
+		composed.appendTemplate(templateLabel(resolved), template)
+		composed.recordTemplate(newTemplateRecord(resolved, template))
+	}
+
+	fixes, err := composed.FixConflicts(20)
+	if err != nil {
+		return IgnoreFile{}, make([]Result, 0), err
+	}
+
+	// Composed files are written in the canonical form of Format, so they pass `gignore fmt -check`
+	composed.Format()
+
+	return composed, fixes, nil
+}
+
+// AddTemplate adds the rules of a template to a section labelled after it, with the same
+// conflict handling as ApplySuggestions: rules that are already present are skipped, rules
+// covered by a broader rule are reported as UNCHANGED, and rules the file negates are reported
+// as REVIEW_RECOMMENDED instead of being added.
+//
+// Parameters:
+//   - source: The TemplateSource to load the template from.
+//   - name: The name of the template.
+//
+// Returns a slice of Result describing every change and an error if the template cannot be
+// loaded, its name contains whitespace, or conflict resolution fails. The IgnoreFile is only
+// modified if every rule is handled.
+//
+// Example:
+//
+//	results, err := ignoreFile.AddTemplate(EmbeddedTemplates(), "jetbrains")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (f *IgnoreFile) AddTemplate(source TemplateSource, name string) ([]Result, error) {
+	template, err := source.Template(name)
+	if err != nil {
+		return make([]Result, 0), err
+	}
+
+	resolved := resolvedTemplateName(source, name)
+	if err := validateTemplateName(resolved); err != nil {
+		return make([]Result, 0), err
+	}
+
+	working := f.clone()
+	results := make([]Result, 0)
+	label := templateLabel(resolved)
+
+	for _, rule := range template.Rules() {
+		added, err := working.addSuggestedRule(label, rule)
+		if err != nil {
+			return make([]Result, 0), err
+		}
+
+		results = append(results, added...)
+	}
+
+	working.recordTemplate(newTemplateRecord(resolved, template))
+	*f = working
+
+	return results, nil
+}
+
+// Adds a rule to a section unless it is present, covered or negated, which are reported instead
+func (f *IgnoreFile) addSuggestedRule(section string, rule Ruler) ([]Result, error) {
+	if f.findRuleIndex(rule) != -1 {
+		return nil, nil
+	}
+
+	added, err := f.AddToSection(section, rule)
+	switch {
+	case errors.Is(err, semanticConflictError):
+		// The file deliberately re-includes what the suggestion ignores
+		return []Result{{Rule: rule, Result: REVIEW_RECOMMENDED, Reason: FIX_UNKNOWN}}, nil
+	case IsConflictError(err):
+		// A broader rule already covers the suggestion
+		return []Result{{Rule: rule, Result: UNCHANGED, Reason: REQUESTED}}, nil
+	}
+
+	return added, err
+}
@@ -0,0 +1,145 @@
+package gignore
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func testTemplateFS() fstest.MapFS {
+	return fstest.MapFS{
+		"Go.gitignore":                    {Data: []byte("*.test\n*.out\n")},
+		"Python.gitignore":                {Data: []byte("__pycache__/\n*.py[cod]\n")},
+		"Global/macOS.gitignore":          {Data: []byte(".DS_Store\n")},
+		"community/Python/Pyre.gitignore": {Data: []byte(".pyre/\n")},
+		".github/PULL_REQUEST.gitignore":  {Data: []byte("ignored\n")},
+		"README.md":                       {Data: []byte("# Templates\n")},
+		"Global/.gitignore":               {Data: []byte("not a template\n")},
+	}
+}
+
+func TestTemplateSourceNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   TemplateSource
+		expected []string
+	}{
+		{
+			name:     "Pass-FS",
+			source:   NewFSTemplateSource(testTemplateFS()),
+			expected: []string{"Global/macOS", "Go", "Python", "community/Python/Pyre"},
+		},
+		{
+			name:     "Pass-Map",
+			source:   NewMapTemplateSource(map[string]string{"company": "*.secret", "base": ".cache/"}),
+			expected: []string{"base", "company"},
+		},
+		{
+			name:     "Pass-Embedded",
+			source:   EmbeddedTemplates(),
+			expected: TemplateNames(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			names, err := tc.source.Names()
+			checkErrors("", err, t)
+
+			if !reflect.DeepEqual(names, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
+
+func TestTemplateSourceTemplate(t *testing.T) {
+	source := NewFSTemplateSource(testTemplateFS())
+
+	tests := []struct {
+		name         string
+		template     string
+		expected     string
+		errorMessage string
+	}{
+		{name: "Pass-ExactName", template: "Go", expected: "*.test\n*.out"},
+		{name: "Pass-CaseInsensitive", template: "python", expected: "__pycache__/\n*.py[cod]"},
+		{name: "Pass-FullPath", template: "global/macos", expected: ".DS_Store"},
+		{name: "Pass-BaseName", template: "macOS", expected: ".DS_Store"},
+		{name: "Fail-Unknown", template: "Cobol", errorMessage: unknownTemplateError.Error()},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			template, err := source.Template(tc.template)
+			checkErrors(tc.errorMessage, err, t)
+
+			if err != nil {
+				return
+			}
+
+			if rendered := Render(&template, RenderOptions{}); rendered != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, rendered)
+			}
+		})
+	}
+}
+
+func TestSearchTemplates(t *testing.T) {
+	matches, err := SearchTemplates(NewFSTemplateSource(testTemplateFS()), "PYTHON")
+	checkErrors("", err, t)
+
+	if expected := []string{"Python", "community/Python/Pyre"}; !reflect.DeepEqual(matches, expected) {
+		t.Errorf("expected %v, got %v", expected, matches)
+	}
+}
+
+func TestComposeTemplatesFrom(t *testing.T) {
+	source := NewMapTemplateSource(map[string]string{
+		"company": "*.secret\n.cache/\n*.log\n",
+		"service": "*.log\ndebug.log\ncoverage/\n",
+	})
+
+	composed, _, err := ComposeTemplatesFrom(source, "company", "service")
+	checkErrors("", err, t)
+
+	expected := templateHeader(t, source, "company", "service") + "\n# company\n*.secret\n.cache/\n\n# service\n*.log\ncoverage/"
+	if rendered := Render(&composed, RenderOptions{}); rendered != expected {
+		t.Errorf("expected %q, got %q", expected, rendered)
+	}
+}
+
+func TestAddTemplate(t *testing.T) {
+	source := NewMapTemplateSource(map[string]string{
+		"company": "*.secret\n.cache/\ndebug.log\n",
+	})
+
+	ignore := NewIgnoreFile()
+	checkErrors("", Parse("*.log\n!.cache/", &ignore), t)
+
+	results, err := ignore.AddTemplate(source, "company")
+	checkErrors("", err, t)
+
+	expected := []string{
+		"ADDED: Rule '*.secret', Reason: REQUESTED",
+		"REVIEW_RECOMMENDED: Rule '.cache/', Reason: FIX_UNKNOWN",
+		"UNCHANGED: Rule 'debug.log', Reason: REQUESTED",
+	}
+
+	logs := make([]string, 0, len(results))
+	for _, result := range results {
+		logs = append(logs, result.Log())
+	}
+
+	if !reflect.DeepEqual(logs, expected) {
+		t.Errorf("expected %v, got %v", expected, logs)
+	}
+
+	expectedFile := templateHeader(t, source, "company") + "\n*.log\n!.cache/\n\n# company\n*.secret"
+	if rendered := Render(&ignore, RenderOptions{}); rendered != expectedFile {
+		t.Errorf("expected %q, got %q", expectedFile, rendered)
+	}
+
+	_, err = ignore.AddTemplate(source, "unknown")
+	checkErrors(unknownTemplateError.Error(), err, t)
+}
@@ -0,0 +1,253 @@
+package gignore
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Suggestion is a rule that would cover a group of paths.
+type Suggestion struct {
+	Rule  Ruler
+	Paths []string
+}
+
+// Log returns a formatted string representation of the Suggestion suitable for logging
+// or display purposes.
+//
+// Example output: "SUGGESTED: Rule '*.log', Covers: 3 paths"
+func (s Suggestion) Log() string {
+	return fmt.Sprintf("SUGGESTED: Rule '%s', Covers: %d paths", s.Rule.Render(), len(s.Paths))
+}
+
+// UntrackedReport lists the files that are neither tracked nor ignored, and the rules that
+// would cover them.
+type UntrackedReport struct {
+	Untracked   []string
+	Suggestions []Suggestion
+}
+
+// Untracked walks a working tree and returns the files that are neither tracked nor ignored,
+// in lexical order. Ignored directories are not walked, and neither is .git. The ignore files
+// themselves are meant to be committed and are not reported. Files a negated rule such as
+// "!debug.log" keeps are not ignored, so they are reported like git status does.
+//
+// Parameters:
+//   - tree: The root of the working tree, e.g. os.DirFS(".").
+//   - tracked: Tracked paths relative to the root of the tree, e.g. from ReadIndex.
+//
+// Returns the untracked paths and an error if the tree cannot be walked.
+func (s IgnoreFileSet) Untracked(tree fs.FS, tracked []string) ([]string, error) {
+	isTracked := make(map[string]bool, len(tracked))
+	for _, trackedPath := range tracked {
+		isTracked[trackedPath] = true
+	}
+
+	untracked := make([]string, 0)
+
+	err := fs.WalkDir(tree, ".", func(entryPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entryPath == ".":
+			return nil
+		case entry.IsDir() && (entry.Name() == ".git" || s.IsIgnored(entryPath+"/")):
+			return fs.SkipDir
+		case entry.IsDir() || isTracked[entryPath] || entry.Name() == s.name || s.IsIgnored(entryPath):
+			return nil
+		}
+
+		untracked = append(untracked, entryPath)
+
+		return nil
+	})
+
+	return untracked, err
+}
+
+// SuggestRules proposes rules that cover untracked paths without covering any tracked path.
+// Paths are grouped, in order of preference:
+//   - By directory: the outermost directory that contains no tracked files becomes a DirectoryRule
+//   - By extension: two or more remaining paths sharing an extension no tracked file uses
+//     become an ExtensionRule
+//   - Every other path becomes a FileRule
+//
+// Directory and file rules for top-level paths match at any depth, so they are anchored to the
+// root ("/tmp", "/debug.log") when they would otherwise cover a tracked path.
+//
+// Parameters:
+//   - untracked: The paths to cover, e.g. from IgnoreFileSet.Untracked.
+//   - tracked: The tracked paths that must stay uncovered, e.g. from ReadIndex.
+//
+// Returns the suggestions, directories first, then extensions, then files, each in lexical order.
+func SuggestRules(untracked, tracked []string) []Suggestion {
+	trackedDirs := make(map[string]bool)
+	trackedExts := make(map[string]bool)
+	for _, trackedPath := range tracked {
+		for dir := path.Dir(trackedPath); dir != "."; dir = path.Dir(dir) {
+			trackedDirs[dir] = true
+		}
+		trackedExts[extensionOf(trackedPath)] = true
+	}
+
+	byDir := make(map[string][]string)
+	byExt := make(map[string][]string)
+	var rest []string
+
+	for _, untrackedPath := range untracked {
+		if dir := outermostUntrackedDir(untrackedPath, trackedDirs); dir != "" {
+			byDir[dir] = append(byDir[dir], untrackedPath)
+			continue
+		}
+
+		if ext := extensionOf(untrackedPath); ext != "" && !trackedExts[ext] {
+			byExt[ext] = append(byExt[ext], untrackedPath)
+			continue
+		}
+
+		rest = append(rest, untrackedPath)
+	}
+
+	suggestions := make([]Suggestion, 0)
+
+	for _, dir := range sortedKeys(byDir) {
+		rule, err := NewDirectoryRule(dir, DIRECTORY, INCLUDE)
+		if err == nil && coversTracked(rule, tracked) {
+			// "tmp/" matches at any depth, so it would also cover a tracked "src/tmp/"
+			rule, err = NewDirectoryRule(dir, ROOT_ONLY, INCLUDE)
+		}
+
+		if err == nil {
+			suggestions = append(suggestions, Suggestion{Rule: rule, Paths: byDir[dir]})
+		}
+	}
+
+	for _, ext := range sortedKeys(byExt) {
+		if len(byExt[ext]) < 2 {
+			rest = append(rest, byExt[ext]...)
+			continue
+		}
+
+		if rule, err := NewExtensionRule(ext, INCLUDE); err == nil {
+			suggestions = append(suggestions, Suggestion{Rule: rule, Paths: byExt[ext]})
+		}
+	}
+
+	sort.Strings(rest)
+	for _, untrackedPath := range rest {
+		rule, err := NewFileRule(untrackedPath, INCLUDE)
+		if err == nil && coversTracked(rule, tracked) {
+			rule, err = NewFileRule("/"+untrackedPath, INCLUDE)
+		}
+
+		if err == nil {
+			suggestions = append(suggestions, Suggestion{Rule: rule, Paths: []string{untrackedPath}})
+		}
+	}
+
+	return suggestions
+}
+
+// Reports whether a rule matches a tracked path or one of its parent directories
+func coversTracked(rule Ruler, tracked []string) bool {
+	for _, trackedPath := range tracked {
+		if matchesRule(rule, trackedPath, false) {
+			return true
+		}
+
+		for dir := path.Dir(trackedPath); dir != "."; dir = path.Dir(dir) {
+			if matchesRule(rule, dir, true) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Returns the outermost parent directory of a path without tracked files, or "" if there is none
+func outermostUntrackedDir(target string, trackedDirs map[string]bool) string {
+	outermost := ""
+	for dir := path.Dir(target); dir != "."; dir = path.Dir(dir) {
+		if trackedDirs[dir] {
+			break
+		}
+		outermost = dir
+	}
+
+	return outermost
+}
+
+// Returns the last extension of a path without the dot, or "" for files without one (and dotfiles)
+func extensionOf(target string) string {
+	name := path.Base(target)
+	if idx := strings.LastIndex(name, "."); idx > 0 {
+		return name[idx+1:]
+	}
+
+	return ""
+}
+
+func sortedKeys(groups map[string][]string) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// FindUntracked reads the index and every .gitignore file of a git repository, lists the
+// files that are neither tracked nor ignored, and suggests rules that would cover them. Files
+// a negated rule keeps are listed, but no rule is suggested for them. The git binary is not needed.
+//
+// Parameters:
+//   - repo: The root directory of a git repository, e.g. os.DirFS(".").
+//
+// Returns an UntrackedReport and an error if the index, an ignore file or the tree cannot be read.
+//
+// Example:
+//
+//	report, err := FindUntracked(os.DirFS("."))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	for _, suggestion := range report.Suggestions {
+//	    fmt.Println(suggestion.Log()) // SUGGESTED: Rule 'tmp/', Covers: 12 paths
+//	}
+func FindUntracked(repo fs.FS) (UntrackedReport, error) {
+	tracked, err := LoadIndex(repo)
+	if err != nil {
+		return UntrackedReport{}, err
+	}
+
+	set, err := LoadIgnoreFileSet(repo, ".gitignore")
+	if err != nil {
+		return UntrackedReport{}, err
+	}
+
+	untracked, err := set.Untracked(repo, tracked)
+	if err != nil {
+		return UntrackedReport{}, err
+	}
+
+	// A rule covering a file a negation keeps on purpose would be overridden anyway
+	uncovered := make([]string, 0, len(untracked))
+	for _, untrackedPath := range untracked {
+		if !set.Match(untrackedPath).Matched() {
+			uncovered = append(uncovered, untrackedPath)
+		}
+	}
+
+	return UntrackedReport{
+		Untracked:   untracked,
+		Suggestions: SuggestRules(uncovered, tracked),
+	}, nil
+}
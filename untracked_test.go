@@ -0,0 +1,151 @@
+package gignore
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFindUntracked(t *testing.T) {
+	repo := testRepository(t)
+	repo["debug.log"] = repo["a.log"]
+	repo["tmp/a.txt"] = repo["main.go"]
+	repo["tmp/cache/b.txt"] = repo["main.go"]
+	repo["coverage.out"] = repo["main.go"]
+	repo["profile.out"] = repo["main.go"]
+	repo["notes"] = repo["main.go"]
+	repo["docs/draft.md"] = repo["main.go"]
+	repo["scratch.go"] = repo["main.go"]
+
+	report, err := FindUntracked(repo)
+	checkErrors("", err, t)
+
+	// Ignore files are meant to be committed. docs/debug.log is kept by a negation in docs/.gitignore,
+	// so git lists it as untracked, but no rule is suggested for it
+	expectedUntracked := []string{
+		"coverage.out",
+		"docs/debug.log",
+		"docs/draft.md",
+		"notes",
+		"profile.out",
+		"scratch.go",
+		"tmp/a.txt",
+		"tmp/cache/b.txt",
+	}
+
+	if !reflect.DeepEqual(report.Untracked, expectedUntracked) {
+		t.Errorf("expected untracked %v, got %v", expectedUntracked, report.Untracked)
+	}
+
+	expectedSuggestions := []string{
+		"SUGGESTED: Rule 'tmp/', Covers: 2 paths",
+		"SUGGESTED: Rule '*.out', Covers: 2 paths",
+		"SUGGESTED: Rule 'docs/draft.md', Covers: 1 paths",
+		"SUGGESTED: Rule 'notes', Covers: 1 paths",
+		"SUGGESTED: Rule 'scratch.go', Covers: 1 paths",
+	}
+
+	if len(report.Suggestions) != len(expectedSuggestions) {
+		t.Fatalf("expected %d suggestions, got %d: %v", len(expectedSuggestions), len(report.Suggestions), report.Suggestions)
+	}
+
+	for idx, suggestion := range report.Suggestions {
+		if suggestion.Log() != expectedSuggestions[idx] {
+			t.Errorf("expected %q, got %q", expectedSuggestions[idx], suggestion.Log())
+		}
+	}
+}
+
+func TestUntracked(t *testing.T) {
+	tree := fstest.MapFS{
+		".gitignore":     {Data: []byte("*.log\n!keep.log\n")},
+		"debug.log":      {},
+		"keep.log":       {},
+		"logs/keep.log":  {},
+		"logs/other.log": {},
+		"main.go":        {},
+	}
+
+	set, err := LoadIgnoreFileSet(tree, ".gitignore")
+	checkErrors("", err, t)
+
+	untracked, err := set.Untracked(tree, []string{"main.go"})
+	checkErrors("", err, t)
+
+	// Files whose last matching rule is a negation are not ignored, so git status lists them
+	expected := []string{"keep.log", "logs/keep.log"}
+	if !reflect.DeepEqual(untracked, expected) {
+		t.Errorf("expected untracked %v, got %v", expected, untracked)
+	}
+}
+
+func TestSuggestRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		untracked []string
+		tracked   []string
+		expected  []string
+	}{
+		{
+			name:      "Pass-OutermostUntrackedDirectory",
+			untracked: []string{"src/gen/a.go", "src/gen/deep/b.go"},
+			tracked:   []string{"src/main.go"},
+			expected:  []string{"src/gen/"},
+		},
+		{
+			name:      "Pass-ExtensionNotTracked",
+			untracked: []string{"a.tmp", "b.tmp"},
+			tracked:   []string{"main.go"},
+			expected:  []string{"*.tmp"},
+		},
+		{
+			name:      "Pass-SingleExtensionFallsBackToFile",
+			untracked: []string{"a.tmp"},
+			tracked:   []string{"main.go"},
+			expected:  []string{"a.tmp"},
+		},
+		{
+			name:      "Pass-TrackedExtensionFallsBackToFile",
+			untracked: []string{"b.go", "c.go"},
+			tracked:   []string{"main.go"},
+			expected:  []string{"b.go", "c.go"},
+		},
+		{
+			name:      "Pass-DirectoryAnchoredAwayFromTrackedDirectory",
+			untracked: []string{"tmp/a.txt"},
+			tracked:   []string{"src/tmp/x"},
+			expected:  []string{"/tmp"},
+		},
+		{
+			name:      "Pass-FileAnchoredAwayFromTrackedFile",
+			untracked: []string{"debug.log"},
+			tracked:   []string{"pkg/debug.log"},
+			expected:  []string{"/debug.log"},
+		},
+		{
+			name:      "Pass-NestedPathsNotAnchored",
+			untracked: []string{"src/gen/a.go", "pkg/debug.out"},
+			tracked:   []string{"src/main.go", "pkg/main.go", "lib/src/gen/b.go", "debug.out"},
+			expected:  []string{"src/gen/", "pkg/debug.out"},
+		},
+		{
+			name:      "Pass-Nothing",
+			untracked: []string{},
+			tracked:   []string{"main.go"},
+			expected:  []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rendered := make([]string, 0)
+			for _, suggestion := range SuggestRules(tc.untracked, tc.tracked) {
+				rendered = append(rendered, suggestion.Rule.Render())
+			}
+
+			if !reflect.DeepEqual(rendered, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, rendered)
+			}
+		})
+	}
+}